@@ -123,12 +123,194 @@ func (articleApi *ArticleApi) ArticleLikesList(c *gin.Context) {
 	}, "Successfully retrieved article likes list", c)
 }
 
+// BookmarkFolders 获取我的收藏夹列表
+func (articleApi *ArticleApi) BookmarkFolders(c *gin.Context) {
+	folders, err := service.ServiceGroupApp.ArticleService.BookmarkFolderList(utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to get bookmark folders:", zap.Error(err))
+		response.FailWithMessage("Failed to get bookmark folders", c)
+		return
+	}
+	response.OkWithData(folders, c)
+}
+
+// BookmarkFolderCreate 创建收藏夹
+func (articleApi *ArticleApi) BookmarkFolderCreate(c *gin.Context) {
+	var req request.BookmarkFolderCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	folder, err := service.ServiceGroupApp.ArticleService.BookmarkFolderCreate(req)
+	if err != nil {
+		global.Log.Error("Failed to create bookmark folder:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(folder, "Successfully created bookmark folder", c)
+}
+
+// BookmarkFolderUpdate 修改收藏夹
+func (articleApi *ArticleApi) BookmarkFolderUpdate(c *gin.Context) {
+	var req request.BookmarkFolderUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	if err := service.ServiceGroupApp.ArticleService.BookmarkFolderUpdate(req); err != nil {
+		global.Log.Error("Failed to update bookmark folder:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully updated bookmark folder", c)
+}
+
+// BookmarkFolderDelete 删除收藏夹，其中的收藏会移到未分类
+func (articleApi *ArticleApi) BookmarkFolderDelete(c *gin.Context) {
+	var req request.BookmarkFolderDelete
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	if err := service.ServiceGroupApp.ArticleService.BookmarkFolderDelete(req); err != nil {
+		global.Log.Error("Failed to delete bookmark folder:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully deleted bookmark folder", c)
+}
+
+// BookmarkMove 把收藏移动到其他收藏夹
+func (articleApi *ArticleApi) BookmarkMove(c *gin.Context) {
+	var req request.BookmarkMove
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	if err := service.ServiceGroupApp.ArticleService.BookmarkMove(req); err != nil {
+		global.Log.Error("Failed to move bookmarks:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully moved bookmarks", c)
+}
+
+// BookmarkNote 修改收藏的私人备注
+func (articleApi *ArticleApi) BookmarkNote(c *gin.Context) {
+	var req request.BookmarkNote
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	if err := service.ServiceGroupApp.ArticleService.BookmarkNote(req); err != nil {
+		global.Log.Error("Failed to update bookmark note:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully updated bookmark note", c)
+}
+
+// ReadingBeacon 客户端上报阅读位置，先写入缓冲区，稍后批量保存
+func (articleApi *ArticleApi) ReadingBeacon(c *gin.Context) {
+	var req request.ReadingBeacon
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	if err := service.ServiceGroupApp.ArticleService.ReadingBeacon(req); err != nil {
+		global.Log.Error("Failed to record reading beacon:", zap.Error(err))
+		response.FailWithMessage("Failed to record reading beacon", c)
+		return
+	}
+	response.OkWithMessage("Successfully recorded reading beacon", c)
+}
+
+// ReadingHistory 获取我的阅读记录
+func (articleApi *ArticleApi) ReadingHistory(c *gin.Context) {
+	var req request.ReadingHistoryList
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	list, total, err := service.ServiceGroupApp.ArticleService.ReadingHistoryList(req)
+	if err != nil {
+		global.Log.Error("Failed to get reading history:", zap.Error(err))
+		response.FailWithMessage("Failed to get reading history", c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:  list,
+		Total: total,
+	}, "Successfully retrieved reading history", c)
+}
+
+// ReadingHistoryClear 删除阅读记录
+func (articleApi *ArticleApi) ReadingHistoryClear(c *gin.Context) {
+	var req request.ReadingHistoryClear
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	if err := service.ServiceGroupApp.ArticleService.ReadingHistoryClear(req); err != nil {
+		global.Log.Error("Failed to clear reading history:", zap.Error(err))
+		response.FailWithMessage("Failed to clear reading history", c)
+		return
+	}
+	response.OkWithMessage("Successfully cleared reading history", c)
+}
+
+// ReadingResume 获取文章上次阅读的位置，用于继续阅读
+func (articleApi *ArticleApi) ReadingResume(c *gin.Context) {
+	var req request.ReadingResume
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	position, err := service.ServiceGroupApp.ArticleService.ReadingResume(req)
+	if err != nil {
+		global.Log.Error("Failed to get reading position:", zap.Error(err))
+		response.FailWithMessage("Failed to get reading position", c)
+		return
+	}
+	response.OkWithData(position, c)
+}
+
+// ArticleFeed 获取关注的作者、标签和类别下的最新文章
+func (articleApi *ArticleApi) ArticleFeed(c *gin.Context) {
+	var req request.ArticleFeed
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	articles, total, err := service.ServiceGroupApp.ArticleService.ArticleFeed(req)
+	if err != nil {
+		global.Log.Error("Failed to get article feed:", zap.Error(err))
+		response.FailWithMessage("Failed to get article feed", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  articles,
+		Total: total,
+	}, c)
+}
+
 func (articleApi *ArticleApi) ArticleCreate(c *gin.Context) {
 	var req request.ArticleCreate
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
+	req.AuthorUUID = utils.GetUUID(c).String()
 	err := service.ServiceGroupApp.ArticleService.ArticleCreate(req)
 	if err != nil {
 		global.Log.Error("Failed to create article:", zap.Error(err))
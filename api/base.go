@@ -4,10 +4,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mojocn/base64Captcha"
 	"go.uber.org/zap"
+	"net/http"
 	"server/global"
 	"server/model/request"
 	"server/model/response"
 	"server/service"
+	"server/utils"
 )
 
 type BaseApi struct {
@@ -46,10 +48,11 @@ func (baseApi *BaseApi) SendEmailVerificationCode(c *gin.Context) {
 		return
 	}
 	if store.Verify(req.CaptchaID, req.Captcha, true) {
-		err := service.ServiceGroupApp.BaseService.SendEmailVerificationCode(c, req.Email)
+		err := service.ServiceGroupApp.BaseService.SendEmailVerificationCode(req.Email, req.Purpose, c.ClientIP())
 		if err != nil {
 			global.Log.Error("Failed to send email:", zap.Error(err))
 			response.FailWithMessage(err.Error(), c)
+			return
 		}
 		response.OkWithMessage("Successfully send email", c)
 		return
@@ -58,6 +61,42 @@ func (baseApi *BaseApi) SendEmailVerificationCode(c *gin.Context) {
 }
 
 func (baseApi *BaseApi) QQLoginURL(c *gin.Context) {
-	url := global.Config.QQ.QQLoginURL()
+	if !global.Config.QQ.Enable {
+		response.FailWithMessage("QQ login is not enabled", c)
+		return
+	}
+	url, err := service.ServiceGroupApp.QQService.QQLoginURL()
+	if err != nil {
+		global.Log.Error("Failed to get QQ login URL:", zap.Error(err))
+		response.FailWithMessage("Failed to get QQ login URL", c)
+		return
+	}
+	response.OkWithData(url, c)
+}
+
+// JWKS 获取用于校验 Access Token 的公钥，格式为 JSON Web Key Set
+func (baseApi *BaseApi) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.JWKS())
+}
+
+// OAuthProviders 获取已启用的第三方登录提供方
+func (baseApi *BaseApi) OAuthProviders(c *gin.Context) {
+	response.OkWithData(service.ServiceGroupApp.OAuthService.OAuthProviders(), c)
+}
+
+// OAuthLoginURL 获取第三方登录的授权地址
+func (baseApi *BaseApi) OAuthLoginURL(c *gin.Context) {
+	var req request.OAuthProvider
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	url, err := service.ServiceGroupApp.OAuthService.OAuthURL(req.Provider, "login", 0)
+	if err != nil {
+		global.Log.Error("Failed to get OAuth login URL:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
 	response.OkWithData(url, c)
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"server/global"
+	"server/model/appTypes"
 	"server/model/request"
 	"server/model/response"
 	"server/service"
@@ -56,6 +57,49 @@ func (commentApi *CommentApi) CommentCreate(c *gin.Context) {
 	response.OkWithMessage("Comment created successfully", c)
 }
 
+// CommentGuestCreate 游客创建评论
+func (commentApi *CommentApi) CommentGuestCreate(c *gin.Context) {
+	var req request.CommentGuestCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if !store.Verify(req.CaptchaID, req.Captcha, true) {
+		response.FailWithMessage("Invalid verification code", c)
+		return
+	}
+	status, err := service.ServiceGroupApp.CommentService.CommentGuestCreate(req)
+	if err != nil {
+		global.Log.Error("Failed to create guest comment:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	switch status {
+	case appTypes.Unverified:
+		response.OkWithDetailed(status, "Please check your email to verify the comment", c)
+	case appTypes.Pending:
+		response.OkWithDetailed(status, "Comment created successfully and is awaiting review", c)
+	default:
+		response.OkWithDetailed(status, "Comment created successfully", c)
+	}
+}
+
+// CommentGuestVerify 通过邮件中的链接验证游客评论
+func (commentApi *CommentApi) CommentGuestVerify(c *gin.Context) {
+	var req request.CommentGuestVerify
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	status, err := service.ServiceGroupApp.CommentService.CommentGuestVerify(req.Token)
+	if err != nil {
+		global.Log.Error("Failed to verify guest comment:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(status, "Comment verified successfully", c)
+}
+
 // CommentDelete 删除评论
 func (commentApi *CommentApi) CommentDelete(c *gin.Context) {
 	var req request.CommentDelete
@@ -104,3 +148,18 @@ func (commentApi *CommentApi) CommentList(c *gin.Context) {
 		Total: total,
 	}, c)
 }
+
+// CommentReview 审核评论
+func (commentApi *CommentApi) CommentReview(c *gin.Context) {
+	var req request.CommentReview
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := service.ServiceGroupApp.CommentService.CommentReview(req); err != nil {
+		global.Log.Error("Failed to review comment:", zap.Error(err))
+		response.FailWithMessage("Failed to review comment", c)
+		return
+	}
+	response.OkWithMessage("Successfully reviewed comment", c)
+}
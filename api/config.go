@@ -151,6 +151,17 @@ func (configApi *ConfigApi) UpdateJwt(c *gin.Context) {
 	response.OkWithMessage("Successfully updated jwt", c)
 }
 
+// RotateJwtKeys 立即轮换 JWT 签名密钥，旧密钥在令牌的有效期内仍可用于校验
+func (configApi *ConfigApi) RotateJwtKeys(c *gin.Context) {
+	err := service.ServiceGroupApp.JwtKeyService.RotateJwtKeys()
+	if err != nil {
+		global.Log.Error("Failed to rotate jwt keys:", zap.Error(err))
+		response.FailWithMessage("Failed to rotate jwt keys", c)
+		return
+	}
+	response.OkWithMessage("Successfully rotated jwt keys", c)
+}
+
 // GetGaode 获取高德配置
 func (configApi *ConfigApi) GetGaode(c *gin.Context) {
 	response.OkWithData(global.Config.Gaode, c)
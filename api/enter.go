@@ -9,8 +9,11 @@ type ApiGroup struct {
 	AdvertisementApi
 	FriendLinkApi
 	FeedbackApi
+	FollowApi
 	WebsiteApi
 	ConfigApi
+	RBACApi
+	QueueApi
 }
 
 var ApiGroupApp = new(ApiGroup)
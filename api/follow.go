@@ -0,0 +1,122 @@
+package api
+
+import (
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+	"server/service"
+	"server/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type FollowApi struct {
+}
+
+// FollowCreate 关注用户、标签或类别
+func (followApi *FollowApi) FollowCreate(c *gin.Context) {
+	var req request.Follow
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	if err := service.ServiceGroupApp.FollowService.FollowCreate(req); err != nil {
+		global.Log.Error("Failed to follow:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully followed", c)
+}
+
+// FollowDelete 取消关注
+func (followApi *FollowApi) FollowDelete(c *gin.Context) {
+	var req request.Follow
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	if err := service.ServiceGroupApp.FollowService.FollowDelete(req); err != nil {
+		global.Log.Error("Failed to unfollow:", zap.Error(err))
+		response.FailWithMessage("Failed to unfollow", c)
+		return
+	}
+	response.OkWithMessage("Successfully unfollowed", c)
+}
+
+// FollowIs 判断是否已关注
+func (followApi *FollowApi) FollowIs(c *gin.Context) {
+	var req request.Follow
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	isFollow, err := service.ServiceGroupApp.FollowService.FollowIs(req)
+	if err != nil {
+		global.Log.Error("Failed to get follow status:", zap.Error(err))
+		response.FailWithMessage("Failed to get follow status", c)
+		return
+	}
+	response.OkWithData(isFollow, c)
+}
+
+// FollowList 获取我的关注列表
+func (followApi *FollowApi) FollowList(c *gin.Context) {
+	var req request.FollowList
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	list, total, err := service.ServiceGroupApp.FollowService.FollowList(req)
+	if err != nil {
+		global.Log.Error("Failed to get follow list:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
+
+// Followers 获取用户的粉丝列表
+func (followApi *FollowApi) Followers(c *gin.Context) {
+	var req request.FollowUsers
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := service.ServiceGroupApp.FollowService.Followers(req)
+	if err != nil {
+		global.Log.Error("Failed to get followers:", zap.Error(err))
+		response.FailWithMessage("Failed to get followers", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
+
+// Following 获取用户关注的用户列表
+func (followApi *FollowApi) Following(c *gin.Context) {
+	var req request.FollowUsers
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := service.ServiceGroupApp.FollowService.Following(req)
+	if err != nil {
+		global.Log.Error("Failed to get following:", zap.Error(err))
+		response.FailWithMessage("Failed to get following", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+	"server/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type QueueApi struct {
+}
+
+// ConsumerStatuses 获取所有消费者的运行状态
+func (queueApi *QueueApi) ConsumerStatuses(c *gin.Context) {
+	response.OkWithData(service.ServiceGroupApp.QueueService.ConsumerStatuses(), c)
+}
+
+// DeadLetterList 查看死信队列中的消息
+func (queueApi *QueueApi) DeadLetterList(c *gin.Context) {
+	var req request.DeadLetterList
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := service.ServiceGroupApp.QueueService.DeadLetterList(req)
+	if err != nil {
+		global.Log.Error("Failed to get dead letters:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: int64(total),
+	}, c)
+}
+
+// DeadLetterReplay 把死信队列中的消息重新发布到原队列
+func (queueApi *QueueApi) DeadLetterReplay(c *gin.Context) {
+	var req request.DeadLetterReplay
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	replayed, err := service.ServiceGroupApp.QueueService.DeadLetterReplay(req)
+	if err != nil {
+		global.Log.Error("Failed to replay dead letters:", zap.Error(err), zap.Int("replayed", replayed))
+		response.FailWithMessage("Replayed "+strconv.Itoa(replayed)+" dead letters before failing: "+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(replayed, "Successfully replayed "+strconv.Itoa(replayed)+" dead letters", c)
+}
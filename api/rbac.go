@@ -0,0 +1,122 @@
+package api
+
+import (
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+	"server/service"
+	"server/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RBACApi struct {
+}
+
+// PermissionList 获取所有权限
+func (rbacApi *RBACApi) PermissionList(c *gin.Context) {
+	list, err := service.ServiceGroupApp.RBACService.PermissionList()
+	if err != nil {
+		global.Log.Error("Failed to get permissions:", zap.Error(err))
+		response.FailWithMessage("Failed to get permissions", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// RoleList 获取所有角色及其权限
+func (rbacApi *RBACApi) RoleList(c *gin.Context) {
+	list, err := service.ServiceGroupApp.RBACService.RoleList()
+	if err != nil {
+		global.Log.Error("Failed to get roles:", zap.Error(err))
+		response.FailWithMessage("Failed to get roles", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// RoleCreate 创建角色
+func (rbacApi *RBACApi) RoleCreate(c *gin.Context) {
+	var req request.RoleCreate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = service.ServiceGroupApp.RBACService.RoleCreate(utils.GetUserID(c), req)
+	if err != nil {
+		global.Log.Error("Failed to create role:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully created role", c)
+}
+
+// RoleUpdate 更新角色
+func (rbacApi *RBACApi) RoleUpdate(c *gin.Context) {
+	var req request.RoleUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = service.ServiceGroupApp.RBACService.RoleUpdate(utils.GetUserID(c), req)
+	if err != nil {
+		global.Log.Error("Failed to update role:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully updated role", c)
+}
+
+// RoleDelete 删除角色
+func (rbacApi *RBACApi) RoleDelete(c *gin.Context) {
+	var req request.RoleDelete
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = service.ServiceGroupApp.RBACService.RoleDelete(req.ID)
+	if err != nil {
+		global.Log.Error("Failed to delete role:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully deleted role", c)
+}
+
+// UserRoles 获取用户的角色
+func (rbacApi *RBACApi) UserRoles(c *gin.Context) {
+	var req request.UserRoles
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, err := service.ServiceGroupApp.RBACService.UserRoles(req.UserID)
+	if err != nil {
+		global.Log.Error("Failed to get user roles:", zap.Error(err))
+		response.FailWithMessage("Failed to get user roles", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// UserRolesUpdate 重新分配用户的角色
+func (rbacApi *RBACApi) UserRolesUpdate(c *gin.Context) {
+	var req request.UserRolesUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = service.ServiceGroupApp.RBACService.UserRolesUpdate(utils.GetUserID(c), req)
+	if err != nil {
+		global.Log.Error("Failed to update user roles:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully updated user roles", c)
+}
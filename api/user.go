@@ -2,7 +2,9 @@ package api
 
 import (
 	"errors"
+	"net/http"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/database"
 	"server/model/request"
 	"server/model/response"
@@ -10,9 +12,7 @@ import (
 	"server/utils"
 	"time"
 
-	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
-	"github.com/go-redis/redis"
 	"go.uber.org/zap"
 )
 
@@ -27,30 +27,8 @@ func (userApi *UserApi) Register(c *gin.Context) {
 		return
 	}
 
-	session := sessions.Default(c)
-	savedEmail := session.Get("email")
-	if savedEmail == nil || savedEmail.(string) != req.Email {
-		global.Log.Info("savedEmail not match", zap.Any("savedEmail", savedEmail), zap.Any("req.email", req.Email))
-		response.FailWithMessage("This email doesn't match the email to be verified", c)
-		return
-	}
-	// 获取会话中存储的邮箱验证码
-	savedCode := session.Get("verificationCode")
-	if savedCode == nil || savedCode.(string) != req.VerificationCode {
-		global.Log.Info("verification code not match", zap.Any("savedCode", savedCode), zap.Any("req.VerificationCode", req.VerificationCode))
-		response.FailWithMessage("Invalid verification code", c)
-		return
-	}
-
-	// 判断邮箱验证码是否过期
-	savedTime := session.Get("expiretime")
-	if savedTime.(int64) < time.Now().Unix() {
-		response.FailWithMessage("The verification code has expired, please resend it", c)
-		return
-	}
-
 	u := database.User{Username: req.Username, Password: req.Password, Email: req.Email}
-	user, err := service.ServiceGroupApp.UserService.Register(u)
+	user, err := service.ServiceGroupApp.UserService.Register(u, req.VerificationCode)
 	if err != nil {
 		global.Log.Error("Failed to register user:", zap.Error(err))
 		response.FailWithMessage(err.Error(), c)
@@ -64,11 +42,83 @@ func (userApi *UserApi) Register(c *gin.Context) {
 func (userApi *UserApi) Login(c *gin.Context) {
 	switch c.Query("flag") {
 	case "qq":
+		userApi.QQLogin(c)
+	case "oauth":
+		userApi.OAuthLogin(c)
 	default:
 		userApi.EmailLogin(c)
 	}
 }
 
+// QQLogin QQ 登录，使用 QQ 回调中的 Authorization Code 换取用户信息
+func (userApi *UserApi) QQLogin(c *gin.Context) {
+	if !global.Config.QQ.Enable {
+		response.FailWithMessage("QQ login is not enabled", c)
+		return
+	}
+	var req request.QQLogin
+	err := c.ShouldBind(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	// 换取 Access Token 之前校验 state，防止登录 CSRF
+	if err := service.ServiceGroupApp.QQService.VerifyState(req.State); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	accessTokenResponse, err := service.ServiceGroupApp.QQService.GetAccessTokenByCode(req.Code)
+	if err != nil {
+		global.Log.Error("Failed to get QQ access token:", zap.Error(err))
+		response.FailWithMessage("Failed to login by QQ", c)
+		return
+	}
+	openIDResponse, err := service.ServiceGroupApp.QQService.GetOpenIDByAccessToken(accessTokenResponse.AccessToken)
+	if err != nil {
+		global.Log.Error("Failed to get QQ openid:", zap.Error(err))
+		response.FailWithMessage("Failed to login by QQ", c)
+		return
+	}
+
+	user, err := service.ServiceGroupApp.UserService.QQLogin(accessTokenResponse.AccessToken, openIDResponse.OpenID)
+	if err != nil {
+		global.Log.Error("Failed to login by QQ:", zap.Error(err))
+		response.FailWithMessage("Failed to login by QQ", c)
+		return
+	}
+	userApi.TwoFactorNext(c, user)
+}
+
+// OAuthLogin 第三方登录，使用提供方回调中的 Authorization Code 换取用户信息
+func (userApi *UserApi) OAuthLogin(c *gin.Context) {
+	var req request.OAuthLogin
+	err := c.ShouldBind(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	info, state, err := service.ServiceGroupApp.OAuthService.OAuthExchange(req.Provider, req.Code, req.State)
+	if err != nil {
+		global.Log.Error("Failed to exchange OAuth code:", zap.Error(err))
+		response.FailWithMessage("Failed to login by "+req.Provider, c)
+		return
+	}
+	if state.Purpose != "login" {
+		response.FailWithMessage("The login state is invalid", c)
+		return
+	}
+
+	user, err := service.ServiceGroupApp.UserService.OAuthLogin(info)
+	if err != nil {
+		global.Log.Error("Failed to login by OAuth:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	userApi.TwoFactorNext(c, user)
+}
+
 func (userApi *UserApi) EmailLogin(c *gin.Context) {
 	var req request.Login
 	err := c.ShouldBind(&req)
@@ -79,14 +129,68 @@ func (userApi *UserApi) EmailLogin(c *gin.Context) {
 	//校验验证码
 	if store.Verify(req.CaptchaID, req.Captcha, true) {
 		u := database.User{Email: req.Email, Password: req.Password}
-		user, err := service.ServiceGroupApp.UserService.EmailLogin(u)
+		user, err := service.ServiceGroupApp.UserService.EmailLogin(u, c.ClientIP())
 		if err != nil {
 			global.Log.Error("Failed to login by email:", zap.Error(err))
+			// 记录失败的登录，供登录日志使用
+			if user.ID != 0 {
+				c.Set("user_id", user.ID)
+			}
+			if errors.Is(err, service.ErrLoginLocked) {
+				c.Set("login_status", http.StatusLocked)
+				response.FailWithMessage(err.Error(), c)
+				return
+			}
+			if errors.Is(err, service.ErrPasswordResetRequired) {
+				c.Set("login_status", http.StatusForbidden)
+				response.FailWithMessage(err.Error(), c)
+				return
+			}
+			c.Set("login_status", http.StatusUnauthorized)
 			response.FailWithMessage("Failed to login", c)
 			return
 		}
+		userApi.TwoFactorNext(c, user)
+		return
+	}
+	response.FailWithMessage("Incorrect verification code", c)
+}
+
+// TwoFactorNext 用户启用了两步验证时返回待验证令牌，否则直接签发令牌
+func (userApi *UserApi) TwoFactorNext(c *gin.Context, user database.User) {
+	if user.Freeze {
+		response.FailWithMessage("The user has been frozen", c)
+		return
+	}
+	if !service.ServiceGroupApp.UserService.TwoFactorEnabled(user.ID) {
 		userApi.TokenNext(c, user)
+		return
 	}
+
+	pending, err := service.ServiceGroupApp.UserService.TwoFactorPending(user.ID)
+	if err != nil {
+		global.Log.Error("Failed to create two-factor pending token:", zap.Error(err))
+		response.FailWithMessage("Failed to login", c)
+		return
+	}
+	response.OkWithDetailed(pending, "Two-factor authentication required", c)
+}
+
+// TwoFactorVerify 校验两步验证的动态码或恢复码，成功后签发令牌
+func (userApi *UserApi) TwoFactorVerify(c *gin.Context) {
+	var req request.TwoFactorVerify
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	user, err := service.ServiceGroupApp.UserService.TwoFactorVerify(req.PendingToken, req.Code)
+	if err != nil {
+		global.Log.Error("Failed to verify two-factor authentication:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	userApi.TokenNext(c, user)
 }
 
 func (userApi *UserApi) TokenNext(c *gin.Context, user database.User) {
@@ -121,67 +225,24 @@ func (userApi *UserApi) TokenNext(c *gin.Context, user database.User) {
 		return
 	}
 
-	// 是否开启了多地点登录拦截
-	if !global.Config.System.UseMultipoint {
-		// 设置刷新令牌并返回
-		utils.SetRefreshToken(c, refreshToken, int(refreshClaim.ExpiresAt.Unix()-time.Now().Unix()))
-		c.Set("user_id", user.ID)
-		response.OkWithDetailed(response.Login{
-			User:                 user,
-			AccessToken:          accessToken,
-			AccessTokenExpiresAt: accessClaims.ExpiresAt.Unix() * 1000,
-		}, "Successful login", c)
+	// 为 Refresh Token 创建登录会话，超出最大会话数时会下线最早的会话
+	if err := service.ServiceGroupApp.SessionService.CreateSession(user.ID, refreshToken, refreshClaim, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		global.Log.Error("Failed to create session:", zap.Error(err))
+		response.FailWithMessage("Failed to create session", c)
 		return
 	}
 
-	if jwtStr, err := service.ServiceGroupApp.JwtService.GetRedisJWT(user.UUID); errors.Is(err, redis.Nil) {
-		if err := service.ServiceGroupApp.JwtService.SetRedisJWT(refreshToken, user.UUID); err != nil {
-			global.Log.Error("Failed to set login status:", zap.Error(err))
-			response.FailWithMessage(err.Error(), c)
-			return
-		}
-
-		//设置刷新令牌并返回
-		utils.SetRefreshToken(c, refreshToken, int(refreshClaim.ExpiresAt.Unix()-time.Now().Unix()))
-		c.Set("user_id", user.ID)
-		response.OkWithDetailed(response.Login{
-			User:                 user,
-			AccessToken:          accessToken,
-			AccessTokenExpiresAt: accessClaims.ExpiresAt.Unix() * 1000,
-		}, "Successful login", c)
-	} else if err != nil {
-		global.Log.Error("Failed to get login status:", zap.Error(err))
-		response.FailWithMessage("Failed to get login status", c)
-	} else {
-		//Redis 中已经存在该用户的JWT，将旧的JWT加入黑名单（限制异地多账户登录）
-
-		var blacklist database.JwtBlacklist
-		blacklist.Jwt = jwtStr
-		if err := service.ServiceGroupApp.JwtService.JoinInBlacklist(blacklist); err != nil {
-			global.Log.Error("Failed to invalidata jwt:", zap.Error(err))
-			response.FailWithMessage("Failed to invalidate jwt", c)
-			return
-		}
-
-		//设置新的JWT到service
-		if err := service.ServiceGroupApp.JwtService.SetRedisJWT(refreshToken, user.UUID); err != nil {
-			global.Log.Error("Failed to set login status:", zap.Error(err))
-			response.FailWithMessage("Failed to set login status", c)
-			return
-		}
-
-		// 设置刷新令牌并返回
-		utils.SetRefreshToken(c, refreshToken, int(refreshClaim.ExpiresAt.Unix()-time.Now().Unix()))
-		c.Set("user_id", user.ID)
-		response.OkWithDetailed(response.Login{
-			User:                 user,
-			AccessToken:          accessToken,
-			AccessTokenExpiresAt: accessClaims.ExpiresAt.Unix() * 1000,
-		}, "Successful login", c)
-	}
-
+	// 设置刷新令牌并返回
+	utils.SetRefreshToken(c, refreshToken, int(refreshClaim.ExpiresAt.Unix()-time.Now().Unix()))
+	c.Set("user_id", user.ID)
+	response.OkWithDetailed(response.Login{
+		User:                 user,
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessClaims.ExpiresAt.Unix() * 1000,
+	}, "Successful login", c)
 }
 
+// ForgotPassword 使用邮箱验证码找回密码，新的客户端应使用 PasswordResetRequest 和 PasswordReset
 func (UserApi *UserApi) ForgotPassword(c *gin.Context) {
 	var req request.ForgotPassword
 	err := c.ShouldBind(&req)
@@ -190,34 +251,56 @@ func (UserApi *UserApi) ForgotPassword(c *gin.Context) {
 		return
 	}
 
-	session := sessions.Default(c)
-	savedEmail := session.Get("email")
-	if savedEmail == nil || savedEmail.(string) != req.Email {
-		global.Log.Info("savedEmail not match", zap.Any("savedEmail", savedEmail), zap.Any("req.email", req.Email))
-		response.FailWithMessage("This email doesn't match the email to be verified", c)
+	// 校验找回密码验证码
+	if err := service.ServiceGroupApp.BaseService.VerifyEmailCode(req.Email, service.EmailCodeReset, req.VerificationCode); err != nil {
+		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	// 获取会话中存储的邮箱验证码
-	savedCode := session.Get("verificationCode")
-	if savedCode == nil || savedCode.(string) != req.VerificationCode {
-		global.Log.Info("verification code not match", zap.Any("savedCode", savedCode), zap.Any("req.VerificationCode", req.VerificationCode))
-		response.FailWithMessage("Invalid verification code", c)
+
+	if err := service.ServiceGroupApp.UserService.ForgotPassword(req); err != nil {
+		global.Log.Error("ForgotPassword failed", zap.Any("err", err))
+		response.FailWithMessage(err.Error(), c)
 		return
 	}
+	response.OkWithMessage("Successfully retrieved", c)
+}
 
-	// 判断邮箱验证码是否过期
-	savedTime := session.Get("expiretime")
-	if savedTime.(int64) < time.Now().Unix() {
-		response.FailWithMessage("The verification code has expired, please resend it", c)
+// PasswordResetRequest 发送密码重置链接
+func (userApi *UserApi) PasswordResetRequest(c *gin.Context) {
+	var req request.PasswordResetRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if !store.Verify(req.CaptchaID, req.Captcha, true) {
+		response.FailWithMessage("Incorrect verification code", c)
 		return
 	}
+	err = service.ServiceGroupApp.UserService.PasswordResetRequest(req.Email)
+	if err != nil {
+		global.Log.Error("Failed to request password reset:", zap.Error(err))
+		response.FailWithMessage("Failed to send reset link", c)
+		return
+	}
+	response.OkWithMessage("If the email address is registered, a reset link has been sent to it", c)
+}
 
-	if err := service.ServiceGroupApp.UserService.ForgotPassword(req); err != nil {
-		global.Log.Error("ForgotPassword failed", zap.Any("err", err))
+// PasswordReset 使用重置链接中的令牌设置新密码
+func (userApi *UserApi) PasswordReset(c *gin.Context) {
+	var req request.PasswordReset
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	response.OkWithMessage("Successfully retrieved", c)
+	err = service.ServiceGroupApp.UserService.PasswordReset(req.Token, req.NewPassword)
+	if err != nil {
+		global.Log.Error("Failed to reset password:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully reset password, please log in again", c)
 }
 
 func (userApi *UserApi) UserCard(c *gin.Context) {
@@ -419,3 +502,349 @@ func (userApi *UserApi) UserLoginList(c *gin.Context) {
 		Total: total,
 	}, c)
 }
+
+// OAuthLinkURL 获取关联第三方账号的授权地址
+func (userApi *UserApi) OAuthLinkURL(c *gin.Context) {
+	var req request.OAuthProvider
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	url, err := service.ServiceGroupApp.OAuthService.OAuthURL(req.Provider, "link", utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to get OAuth link URL:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithData(url, c)
+}
+
+// OAuthLink 关联第三方账号
+func (userApi *UserApi) OAuthLink(c *gin.Context) {
+	var req request.OAuthLogin
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	userID := utils.GetUserID(c)
+	info, state, err := service.ServiceGroupApp.OAuthService.OAuthExchange(req.Provider, req.Code, req.State)
+	if err != nil {
+		global.Log.Error("Failed to exchange OAuth code:", zap.Error(err))
+		response.FailWithMessage("Failed to link "+req.Provider, c)
+		return
+	}
+	if state.Purpose != "link" || state.UserID != userID {
+		response.FailWithMessage("The link state is invalid", c)
+		return
+	}
+
+	err = service.ServiceGroupApp.UserService.OAuthLink(userID, info)
+	if err != nil {
+		global.Log.Error("Failed to link OAuth account:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully linked", c)
+}
+
+// OAuthUnlink 解除第三方账号关联
+func (userApi *UserApi) OAuthUnlink(c *gin.Context) {
+	var req request.OAuthProvider
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = service.ServiceGroupApp.UserService.OAuthUnlink(utils.GetUserID(c), req.Provider)
+	if err != nil {
+		global.Log.Error("Failed to unlink OAuth account:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully unlinked", c)
+}
+
+// OAuthIdentities 获取已关联的第三方账号
+func (userApi *UserApi) OAuthIdentities(c *gin.Context) {
+	identities, err := service.ServiceGroupApp.UserService.OAuthIdentities(utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to get OAuth identities:", zap.Error(err))
+		response.FailWithMessage("Failed to get OAuth identities", c)
+		return
+	}
+	response.OkWithData(identities, c)
+}
+
+// TwoFactorStatus 获取当前用户是否已启用两步验证
+func (userApi *UserApi) TwoFactorStatus(c *gin.Context) {
+	response.OkWithData(service.ServiceGroupApp.UserService.TwoFactorEnabled(utils.GetUserID(c)), c)
+}
+
+// TwoFactorEnroll 登记两步验证，返回密钥和供身份验证器扫码的地址
+func (userApi *UserApi) TwoFactorEnroll(c *gin.Context) {
+	enroll, err := service.ServiceGroupApp.UserService.TwoFactorEnroll(utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to enroll two-factor authentication:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithData(enroll, c)
+}
+
+// TwoFactorConfirm 确认启用两步验证，返回恢复码
+func (userApi *UserApi) TwoFactorConfirm(c *gin.Context) {
+	var req request.TwoFactorConfirm
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	codes, err := service.ServiceGroupApp.UserService.TwoFactorConfirm(utils.GetUserID(c), req.Code)
+	if err != nil {
+		global.Log.Error("Failed to confirm two-factor authentication:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(codes, "Successfully enabled two-factor authentication", c)
+}
+
+// TwoFactorDisable 关闭两步验证，需要重新输入密码，没有密码的账户需要输入动态码或恢复码
+func (userApi *UserApi) TwoFactorDisable(c *gin.Context) {
+	var req request.TwoFactorDisable
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = service.ServiceGroupApp.UserService.TwoFactorDisable(utils.GetUserID(c), req)
+	if err != nil {
+		global.Log.Error("Failed to disable two-factor authentication:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully disabled two-factor authentication", c)
+}
+
+// SessionList 获取当前用户的登录会话列表
+func (userApi *UserApi) SessionList(c *gin.Context) {
+	var tokenID string
+	if claims, err := utils.GetRefreshClaims(c); err == nil {
+		tokenID = claims.ID
+	}
+	list, err := service.ServiceGroupApp.SessionService.SessionList(utils.GetUserID(c), tokenID)
+	if err != nil {
+		global.Log.Error("Failed to get session list:", zap.Error(err))
+		response.FailWithMessage("Failed to get session list", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// SessionRevoke 下线指定的登录会话
+func (userApi *UserApi) SessionRevoke(c *gin.Context) {
+	var req request.SessionRevoke
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = service.ServiceGroupApp.SessionService.SessionRevoke(utils.GetUserID(c), req.ID)
+	if err != nil {
+		global.Log.Error("Failed to revoke session:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully revoked session", c)
+}
+
+// SessionRevokeOthers 下线除当前会话以外的所有登录会话
+func (userApi *UserApi) SessionRevokeOthers(c *gin.Context) {
+	claims, err := utils.GetRefreshClaims(c)
+	if err != nil {
+		response.FailWithMessage("Failed to get current session", c)
+		return
+	}
+	err = service.ServiceGroupApp.SessionService.SessionRevokeOthers(utils.GetUserID(c), claims.ID)
+	if err != nil {
+		global.Log.Error("Failed to revoke other sessions:", zap.Error(err))
+		response.FailWithMessage("Failed to revoke other sessions", c)
+		return
+	}
+	response.OkWithMessage("Successfully revoked other sessions", c)
+}
+
+// UserUnlock 解除用户的登录锁定
+func (userApi *UserApi) UserUnlock(c *gin.Context) {
+	var req request.UserOperation
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = service.ServiceGroupApp.UserService.UserUnlock(req.ID)
+	if err != nil {
+		global.Log.Error("Failed to unlock user:", zap.Error(err))
+		response.FailWithMessage("Failed to unlock user", c)
+		return
+	}
+	response.OkWithMessage("Successfully unlocked user", c)
+}
+
+// UserChangeEmail 修改邮箱，需要新邮箱的验证码
+func (userApi *UserApi) UserChangeEmail(c *gin.Context) {
+	var req request.UserChangeEmail
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	err = service.ServiceGroupApp.UserService.UserChangeEmail(req)
+	if err != nil {
+		global.Log.Error("Failed to change email:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully changed email", c)
+}
+
+// UserDeletionRequest 申请注销账户
+func (userApi *UserApi) UserDeletionRequest(c *gin.Context) {
+	var req request.UserDeletionRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	scheduledAt, err := service.ServiceGroupApp.UserService.UserDeletionRequest(utils.GetUserID(c), req.Password)
+	if err != nil {
+		global.Log.Error("Failed to request account deletion:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(scheduledAt, "Your account will be deleted after the cooling-off period", c)
+}
+
+// UserDeletionCancel 撤销注销账户
+func (userApi *UserApi) UserDeletionCancel(c *gin.Context) {
+	err := service.ServiceGroupApp.UserService.UserDeletionCancel(utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to cancel account deletion:", zap.Error(err))
+		response.FailWithMessage("Failed to cancel account deletion", c)
+		return
+	}
+	response.OkWithMessage("Successfully cancelled account deletion", c)
+}
+
+// UserExport 申请导出个人数据，导出完成后会以邮件发送下载链接
+func (userApi *UserApi) UserExport(c *gin.Context) {
+	err := service.ServiceGroupApp.UserService.UserExportRequest(utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to request data export:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Your data export has started, the download link will be sent to your email", c)
+}
+
+// UserExportDownload 通过邮件中的限时链接下载个人数据导出文件
+func (userApi *UserApi) UserExportDownload(c *gin.Context) {
+	var req request.UserExportDownload
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	path, err := service.ServiceGroupApp.UserService.UserExportFile(req.Token)
+	if err != nil {
+		global.Log.Error("Failed to download data export:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	c.FileAttachment(path, "personal-data-export.zip")
+}
+
+// UserPermissions 获取当前用户拥有的权限，用于前端展示管理菜单
+func (userApi *UserApi) UserPermissions(c *gin.Context) {
+	permissions, err := service.ServiceGroupApp.RBACService.UserPermissions(utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to get user permissions:", zap.Error(err))
+		response.FailWithMessage("Failed to get user permissions", c)
+		return
+	}
+	response.OkWithData(permissions, c)
+}
+
+// TokenScopes 获取个人访问令牌可用的权限范围
+func (userApi *UserApi) TokenScopes(c *gin.Context) {
+	response.OkWithData(appTypes.Scopes, c)
+}
+
+// TokenList 获取个人访问令牌列表
+func (userApi *UserApi) TokenList(c *gin.Context) {
+	list, err := service.ServiceGroupApp.PersonalAccessTokenService.TokenList(utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to get personal access tokens:", zap.Error(err))
+		response.FailWithMessage("Failed to get personal access tokens", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// TokenCreate 创建个人访问令牌，令牌明文只返回这一次
+func (userApi *UserApi) TokenCreate(c *gin.Context) {
+	var req request.PersonalAccessTokenCreate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	token, pat, err := service.ServiceGroupApp.PersonalAccessTokenService.TokenCreate(req)
+	if err != nil {
+		global.Log.Error("Failed to create personal access token:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PersonalAccessTokenCreate{
+		Token:               token,
+		PersonalAccessToken: pat,
+	}, "Make sure to copy your personal access token now, you won't be able to see it again", c)
+}
+
+// TokenRevoke 撤销个人访问令牌
+func (userApi *UserApi) TokenRevoke(c *gin.Context) {
+	var req request.PersonalAccessTokenRevoke
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = service.ServiceGroupApp.PersonalAccessTokenService.TokenRevoke(utils.GetUserID(c), req.ID)
+	if err != nil {
+		global.Log.Error("Failed to revoke personal access token:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully revoked personal access token", c)
+}
+
+// LoginAlertReject 用户确认异常登录不是本人操作，下线所有会话并返回用于重置密码的令牌
+func (userApi *UserApi) LoginAlertReject(c *gin.Context) {
+	var req request.LoginAlertReject
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	resetToken, err := service.ServiceGroupApp.UserService.LoginAlertReject(req.Token)
+	if err != nil {
+		global.Log.Error("Failed to reject login alert:", zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.LoginAlertReject{ResetToken: resetToken}, "All sessions have been signed out, please reset your password", c)
+}
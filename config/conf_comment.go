@@ -0,0 +1,8 @@
+package config
+
+// Comment 评论配置
+type Comment struct {
+	GuestEnable      bool `json:"guest_enable" yaml:"guest_enable"`             // 是否允许未登录的游客发表评论
+	GuestModeration  bool `json:"guest_moderation" yaml:"guest_moderation"`     // 游客评论是否需要管理员审核后才公开
+	GuestEmailVerify bool `json:"guest_email_verify" yaml:"guest_email_verify"` // 游客评论是否需要先点击邮件中的验证链接
+}
@@ -0,0 +1,8 @@
+package config
+
+// EventBus 事件总线配置
+type EventBus struct {
+	Type        string   `json:"type" yaml:"type"`                 // 事件总线的实现，"rabbitmq" 使用 RabbitMQ，"memory" 在进程内传递事件，不需要消息中间件
+	BufferSize  int      `json:"buffer_size" yaml:"buffer_size"`   // memory 实现中每个主题最多缓存的事件数量，超出后发布失败
+	RetryDelays []string `json:"retry_delays" yaml:"retry_delays"` // 事件处理失败后每次重试前等待的时间，例如 ["5s", "30s", "1d"]，重试次数用完后进入死信队列
+}
@@ -0,0 +1,7 @@
+package config
+
+// Export 用户数据导出配置
+type Export struct {
+	Path   string `json:"path" yaml:"path"`     // 导出文件的保存目录，不能是对外公开的静态目录
+	Expiry string `json:"expiry" yaml:"expiry"` // 下载链接的有效期，例如 "24h"，过期后导出文件会被清理
+}
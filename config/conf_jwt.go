@@ -2,9 +2,12 @@ package config
 
 // Jwt jwt 配置
 type Jwt struct {
-	AccessTokenSecret      string `json:"access_token_secret" yaml:"access_token_secret"`             // 用于生成和验证访问令牌的密钥
-	RefreshTokenSecret     string `json:"refresh_token_secret" yaml:"refresh_token_secret"`           // 用于生成和验证刷新令牌的密钥
+	AccessTokenSecret      string `json:"access_token_secret" yaml:"access_token_secret"`             // 用于验证旧版本不含 kid 的访问令牌的密钥，签名密钥加载失败时也用于生成访问令牌
+	RefreshTokenSecret     string `json:"refresh_token_secret" yaml:"refresh_token_secret"`           // 用于验证旧版本不含 kid 的刷新令牌的密钥，签名密钥加载失败时也用于生成刷新令牌
+	Algorithm              string `json:"algorithm" yaml:"algorithm"`                                 // 签名算法："HS256"、"RS256" 或 "EdDSA"，修改后会在下一次检查时轮换密钥
+	KeyRotationInterval    string `json:"key_rotation_interval" yaml:"key_rotation_interval"`         // 签名密钥的轮换周期，例如 "30d"，留空表示不自动轮换；旧密钥在令牌的有效期内仍可用于校验
 	AccessTokenExpiryTime  string `json:"access_token_expiry_time" yaml:"access_token_expiry_time"`   // 访问令牌的过期时间，例如 "15m" 表示 15 分钟
 	RefreshTokenExpiryTime string `json:"refresh_token_expiry_time" yaml:"refresh_token_expiry_time"` // 刷新令牌的过期时间，例如 "30d" 表示 30 天
 	Issuer                 string `json:"issuer" yaml:"issuer"`                                       // JWT 的签发者信息，通常是应用或服务的名称
+	RefreshReuseGrace      string `json:"refresh_reuse_grace" yaml:"refresh_reuse_grace"`             // 刷新令牌轮换后旧令牌的宽限期，用于容忍并发请求，例如 "10s"，超出后再次使用旧令牌会下线整个会话
 }
@@ -0,0 +1,57 @@
+package config
+
+// OAuth 第三方 OAuth2 / OpenID Connect 登录配置
+type OAuth struct {
+	Enable    bool            `json:"enable" yaml:"enable"`       // 是否启用第三方登录，true 表示启用，false 表示禁用
+	Providers []OAuthProvider `json:"providers" yaml:"providers"` // 登录提供方列表，可以配置任意多个
+}
+
+// OAuthProvider 单个 OAuth2 / OpenID Connect 登录提供方
+type OAuthProvider struct {
+	Name         string   `json:"name" yaml:"name"`                   // 提供方的唯一名称，用于接口中的 provider 参数，例如 "github"
+	DisplayName  string   `json:"display_name" yaml:"display_name"`   // 展示给用户的名称，例如 "GitHub"
+	Type         string   `json:"type" yaml:"type"`                   // 提供方类型："oidc"、"oauth2" 或 "github"
+	ClientID     string   `json:"client_id" yaml:"client_id"`         // 客户端 ID
+	ClientSecret string   `json:"client_secret" yaml:"client_secret"` // 客户端密钥
+	DiscoveryURL string   `json:"discovery_url" yaml:"discovery_url"` // OIDC 发现地址，例如 https://sso.example.com/.well-known/openid-configuration
+	AuthURL      string   `json:"auth_url" yaml:"auth_url"`           // 授权地址，配置了发现地址时可留空
+	TokenURL     string   `json:"token_url" yaml:"token_url"`         // 获取令牌的地址，配置了发现地址时可留空
+	UserInfoURL  string   `json:"user_info_url" yaml:"user_info_url"` // 获取用户信息的地址，配置了发现地址时可留空
+	Scopes       []string `json:"scopes" yaml:"scopes"`               // 申请的权限范围，例如 ["openid", "profile", "email"]
+	RedirectURI  string   `json:"redirect_uri" yaml:"redirect_uri"`   // 回调地址
+	SubjectField string   `json:"subject_field" yaml:"subject_field"` // 用户信息中唯一标识的字段，留空时 github 为 "id"，其他为 "sub"
+	EmailField   string   `json:"email_field" yaml:"email_field"`     // 用户信息中邮箱的字段，留空时为 "email"
+	NameField    string   `json:"name_field" yaml:"name_field"`       // 用户信息中昵称的字段，留空时 github 为 "login"，其他为 "name"
+	AvatarField  string   `json:"avatar_field" yaml:"avatar_field"`   // 用户信息中头像的字段，留空时 github 为 "avatar_url"，其他为 "picture"
+}
+
+// Provider 根据名称查找已配置的登录提供方
+func (o OAuth) Provider(name string) (OAuthProvider, bool) {
+	for _, provider := range o.Providers {
+		if provider.Name == name {
+			return provider, true
+		}
+	}
+	return OAuthProvider{}, false
+}
+
+// Fields 返回用户信息中唯一标识、邮箱、昵称和头像对应的字段，未配置时按提供方类型取默认值
+func (p OAuthProvider) Fields() (subject, email, name, avatar string) {
+	subject, email, name, avatar = "sub", "email", "name", "picture"
+	if p.Type == "github" {
+		subject, name, avatar = "id", "login", "avatar_url"
+	}
+	if p.SubjectField != "" {
+		subject = p.SubjectField
+	}
+	if p.EmailField != "" {
+		email = p.EmailField
+	}
+	if p.NameField != "" {
+		name = p.NameField
+	}
+	if p.AvatarField != "" {
+		avatar = p.AvatarField
+	}
+	return
+}
@@ -1,16 +1,32 @@
 package config
 
+import (
+	"net/url"
+	"strings"
+)
+
 // QQ qq 登录配置，详情请见 https://connect.qq.com/
 type QQ struct {
 	Enable      bool   `json:"enable" yaml:"enable"`             // 是否启用 qq 登录，true 表示启用，false 表示禁用
 	AppID       string `json:"app_id" yaml:"app_id"`             // 应用 ID
 	AppKey      string `json:"app_key" yaml:"app_key"`           // 应用密钥
 	RedirectURI string `json:"redirect_uri" yaml:"redirect_uri"` // 网站回调域
+	APIBaseURL  string `json:"api_base_url" yaml:"api_base_url"` // QQ 互联接口地址，默认为 https://graph.qq.com，可指向本地的模拟服务用于测试
+}
+
+// BaseURL 返回 QQ 互联接口地址，未配置时使用官方地址
+func (qq QQ) BaseURL() string {
+	if qq.APIBaseURL == "" {
+		return "https://graph.qq.com"
+	}
+	return strings.TrimRight(qq.APIBaseURL, "/")
 }
 
-func (qq QQ) QQLoginURL() string {
-	return "https://graph.qq.com/oauth2.0/authorize?" +
+// QQLoginURL 返回 QQ 登录的授权地址，state 会在回调时原样返回，用于防止 CSRF
+func (qq QQ) QQLoginURL(state string) string {
+	return qq.BaseURL() + "/oauth2.0/authorize?" +
 		"response_type=code&" +
 		"client_id=" + qq.AppID + "&" +
-		"redirect_uri=" + qq.RedirectURI
+		"redirect_uri=" + url.QueryEscape(qq.RedirectURI) + "&" +
+		"state=" + url.QueryEscape(state)
 }
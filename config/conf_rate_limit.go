@@ -0,0 +1,13 @@
+package config
+
+// RateLimit 接口限流配置
+type RateLimit struct {
+	Enable bool                     `json:"enable" yaml:"enable"` // 是否启用限流，true 表示启用，false 表示禁用
+	Routes map[string]RateLimitRule `json:"routes" yaml:"routes"` // 各路由的限流规则，键为路由名称，例如 "comment/create"
+}
+
+// RateLimitRule 单个路由的限流规则，在滑动时间窗口内最多允许 Limit 次请求
+type RateLimitRule struct {
+	Limit  int    `json:"limit" yaml:"limit"`   // 时间窗口内允许的最大请求次数
+	Window string `json:"window" yaml:"window"` // 时间窗口的长度，例如 "1m" 表示 1 分钟
+}
@@ -0,0 +1,40 @@
+package config
+
+// Security 账户安全配置
+type Security struct {
+	PasswordPolicy PasswordPolicy `json:"password_policy" yaml:"password_policy"` // 密码策略
+	Lockout        Lockout        `json:"lockout" yaml:"lockout"`                 // 登录失败锁定策略
+	DeletionDelay  string         `json:"deletion_delay" yaml:"deletion_delay"`   // 注销账户的冷静期，例如 "7d"，冷静期内可以撤销注销
+	LoginAlert     LoginAlert     `json:"login_alert" yaml:"login_alert"`         // 异常登录提醒
+}
+
+// PasswordPolicy 密码策略，用于注册、找回密码和修改密码
+type PasswordPolicy struct {
+	MinLength          int  `json:"min_length" yaml:"min_length"`                     // 密码最小长度
+	MaxLength          int  `json:"max_length" yaml:"max_length"`                     // 密码最大长度，0 表示不限制
+	RequireUpper       bool `json:"require_upper" yaml:"require_upper"`               // 是否必须包含大写字母
+	RequireLower       bool `json:"require_lower" yaml:"require_lower"`               // 是否必须包含小写字母
+	RequireDigit       bool `json:"require_digit" yaml:"require_digit"`               // 是否必须包含数字
+	RequireSymbol      bool `json:"require_symbol" yaml:"require_symbol"`             // 是否必须包含特殊字符
+	RejectPersonalInfo bool `json:"reject_personal_info" yaml:"reject_personal_info"` // 是否拒绝包含邮箱或用户名的密码
+}
+
+// Lockout 登录失败锁定策略，按账户和 IP 分别计数，每次锁定的时长翻倍，直到达到最大锁定时长
+type Lockout struct {
+	Enable          bool   `json:"enable" yaml:"enable"`                       // 是否启用登录失败锁定
+	MaxAttempts     int    `json:"max_attempts" yaml:"max_attempts"`           // 同一账户在统计窗口内允许的失败次数
+	IPMaxAttempts   int    `json:"ip_max_attempts" yaml:"ip_max_attempts"`     // 同一 IP 在统计窗口内允许的失败次数
+	Window          string `json:"window" yaml:"window"`                       // 失败次数的统计窗口，例如 "15m"
+	LockDuration    string `json:"lock_duration" yaml:"lock_duration"`         // 首次锁定的时长，例如 "5m"
+	MaxLockDuration string `json:"max_lock_duration" yaml:"max_lock_duration"` // 最长锁定时长，例如 "24h"
+}
+
+// LoginAlert 异常登录提醒，登录的设备或城市在历史登录记录中没有出现过时向用户发送邮件
+type LoginAlert struct {
+	Enable        bool   `json:"enable" yaml:"enable"`                 // 是否启用异常登录提醒
+	NewDevice     bool   `json:"new_device" yaml:"new_device"`         // 是否提醒新的操作系统、设备和浏览器组合
+	NewLocation   bool   `json:"new_location" yaml:"new_location"`     // 是否提醒新的登录城市
+	MinHistory    int    `json:"min_history" yaml:"min_history"`       // 至少有多少次成功的历史登录才开始提醒，避免首次登录就提醒
+	HistoryWindow string `json:"history_window" yaml:"history_window"` // 比较的历史登录记录的时间范围，例如 "90d"
+	LinkExpiry    string `json:"link_expiry" yaml:"link_expiry"`       // 提醒邮件中"不是我本人"链接的有效期，例如 "72h"
+}
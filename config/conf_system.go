@@ -8,15 +8,16 @@ import (
 
 // System 系统配置
 type System struct {
-	Host           string `json:"-" yaml:"host"`                          // 服务器绑定的主机地址，通常为 0.0.0.0 表示监听所有可用地址
-	Port           int    `json:"-" yaml:"port"`                          // 服务器监听的端口号，通常用于 HTTP 服务
-	Env            string `json:"-" yaml:"env"`                           // Gin 的环境类型，例如 "debug"、"release" 或 "test"
-	RouterPrefix   string `json:"-" yaml:"router_prefix"`                 // API 路由前缀，用于构建 API 路径
-	UseMultipoint  bool   `json:"use_multipoint" yaml:"use_multipoint"`   // 是否启用多点登录拦截，防止同一账户在多个地方同时登录
-	SessionsSecret string `json:"sessions_secret" yaml:"sessions_secret"` // 用于加密会话的密钥，确保会话数据的安全性
-	OssType        string `json:"oss_type" yaml:"oss_type"`               // 对应的对象存储服务类型，如 "local" 或 "qiniu"
-	Admin_Email    string `json:"-" yaml:"admin_email"`                   // 管理员的电子邮件地址，用于系统通知或管理
-	Admin_Password string `json:"-" yaml:"admin_password"`                // 管理员的密码，用于登录系统
+	Host            string `json:"-" yaml:"host"`                              // 服务器绑定的主机地址，通常为 0.0.0.0 表示监听所有可用地址
+	Port            int    `json:"-" yaml:"port"`                              // 服务器监听的端口号，通常用于 HTTP 服务
+	Env             string `json:"-" yaml:"env"`                               // Gin 的环境类型，例如 "debug"、"release" 或 "test"
+	RouterPrefix    string `json:"-" yaml:"router_prefix"`                     // API 路由前缀，用于构建 API 路径
+	MaxSessions     int    `json:"max_sessions" yaml:"max_sessions"`           // 每个账户允许同时在线的最大会话数，超出时最早的会话会被下线，0 表示不限制
+	RequireAdmin2FA bool   `json:"require_admin_2fa" yaml:"require_admin_2fa"` // 是否要求所有拥有管理权限的用户启用两步验证，未启用的用户无法访问管理接口
+	SessionsSecret  string `json:"sessions_secret" yaml:"sessions_secret"`     // 用于加密会话的密钥，确保会话数据的安全性
+	OssType         string `json:"oss_type" yaml:"oss_type"`                   // 对应的对象存储服务类型，如 "local" 或 "qiniu"
+	Admin_Email     string `json:"-" yaml:"admin_email"`                       // 管理员的电子邮件地址，用于系统通知或管理
+	Admin_Password  string `json:"-" yaml:"admin_password"`                    // 管理员的密码，用于登录系统
 }
 
 func (s System) Addr() string {
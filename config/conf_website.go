@@ -21,4 +21,5 @@ type Website struct {
 	Email                string `json:"email" yaml:"email"`                                   // 邮箱
 	QQImage              string `json:"qq_image" yaml:"qq_image"`                             // QQ 图片链接
 	WechatImage          string `json:"wechat_image" yaml:"wechat_image"`                     // 微信图片链接
+	URL                  string `json:"url" yaml:"url"`                                       // 网站地址，用于生成邮件中的链接，例如 https://blog.example.com
 }
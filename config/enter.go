@@ -1,18 +1,24 @@
 package config
 
 type Config struct {
-	Captcha  Captcha  `json:"captcha" yaml:"captcha"`
-	Email    Email    `json:"email" yaml:"email"`
-	ES       ES       `json:"es" yaml:"es"`
-	Gaode    Gaode    `json:"gaode" yaml:"gaode"`
-	Jwt      Jwt      `json:"jwt" yaml:"jwt"`
-	Mysql    Mysql    `json:"mysql" yaml:"mysql"`
-	Qiniu    Qiniu    `json:"qiniu" yaml:"qiniu"`
-	QQ       QQ       `json:"qq" yaml:"qq"`
-	Redis    Redis    `json:"redis" yaml:"redis"`
-	System   System   `json:"system" yaml:"system"`
-	Upload   Upload   `json:"upload" yaml:"upload"`
-	Website  Website  `json:"website" yaml:"website"`
-	Zap      Zap      `json:"zap" yaml:"zap"`
-	RabbitMQ RabbitMQ `json:"rabbitmq" yaml:"rabbitmq"`
+	Captcha   Captcha   `json:"captcha" yaml:"captcha"`
+	Comment   Comment   `json:"comment" yaml:"comment"`
+	Email     Email     `json:"email" yaml:"email"`
+	ES        ES        `json:"es" yaml:"es"`
+	EventBus  EventBus  `json:"event_bus" yaml:"event_bus"`
+	Export    Export    `json:"export" yaml:"export"`
+	Gaode     Gaode     `json:"gaode" yaml:"gaode"`
+	Jwt       Jwt       `json:"jwt" yaml:"jwt"`
+	Mysql     Mysql     `json:"mysql" yaml:"mysql"`
+	OAuth     OAuth     `json:"oauth" yaml:"oauth"`
+	Qiniu     Qiniu     `json:"qiniu" yaml:"qiniu"`
+	QQ        QQ        `json:"qq" yaml:"qq"`
+	Redis     Redis     `json:"redis" yaml:"redis"`
+	Security  Security  `json:"security" yaml:"security"`
+	System    System    `json:"system" yaml:"system"`
+	Upload    Upload    `json:"upload" yaml:"upload"`
+	Website   Website   `json:"website" yaml:"website"`
+	Zap       Zap       `json:"zap" yaml:"zap"`
+	RabbitMQ  RabbitMQ  `json:"rabbitmq" yaml:"rabbitmq"`
+	RateLimit RateLimit `json:"rate_limit" yaml:"rate_limit"`
 }
@@ -12,10 +12,10 @@ func InitConf() *config.Config {
 	c := &config.Config{}
 	yamlConf, err := utils.LoadYAML()
 	if err != nil {
-		log.Fatal("Failed to load configration: %v", err)
+		log.Fatalf("Failed to load configration: %v", err)
 	}
 	if err = yaml.Unmarshal(yamlConf, c); err != nil {
-		log.Fatal("Failed to unmarshal configration: %v", err)
+		log.Fatalf("Failed to unmarshal configration: %v", err)
 	}
 	return c
 }
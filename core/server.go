@@ -3,9 +3,10 @@ package core
 import (
 	"server/global"
 	"server/initialize"
+	"server/model/elasticsearch"
+	"server/service"
 
 	"go.uber.org/zap"
-	// "server/service"
 )
 
 type server interface {
@@ -17,11 +18,33 @@ func RunServer() {
 	addr := global.Config.System.Addr()
 	Router := initialize.InitRouter()
 
-	// 加载所有的 JWT 黑名单，存入本地缓存
-	// TODO service.LoadAll()
+	// 加载 JWT 签名密钥，并订阅其他实例的密钥轮换通知
+	if err := service.ServiceGroupApp.JwtKeyService.LoadJwtKeys(); err != nil {
+		global.Log.Error("Failed to load JWT keys, falling back to the secrets in the configuration:", zap.Error(err))
+	}
+	go service.SubscribeJwtKeys()
+
+	// 在已有的文章索引中补充新增字段的映射
+	if err := service.ServiceGroupApp.EsService.IndexPutMapping(elasticsearch.ArticleIndex(), elasticsearch.ArticleMappingUpdate()); err != nil {
+		global.Log.Error("Failed to update article mapping:", zap.Error(err))
+	}
+
+	// 同步权限表和内置角色
+	if err := service.ServiceGroupApp.RBACService.InitRBAC(); err != nil {
+		global.Log.Error("Failed to initialize roles and permissions:", zap.Error(err))
+	}
+
+	// 加载所有的 JWT 黑名单，存入本地缓存，并订阅其他实例的黑名单更新
+	service.LoadAll()
+	go service.SubscribeBlacklist()
 
 	// 初始化服务器并启动
 	s := initServer(addr, Router)
 	global.Log.Info("server run success on ", zap.String("address", addr))
-	global.Log.Error(s.ListenAndServe().Error())
+	if err := s.ListenAndServe(); err != nil {
+		global.Log.Error(err.Error())
+	}
+
+	// 服务器收到 SIGTERM 或 SIGINT 后会停止接受请求，此时停止订阅者并处理完已经收到的事件
+	global.EventBus.Close()
 }
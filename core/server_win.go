@@ -4,19 +4,45 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// gracefulServer 收到 SIGINT 或 SIGTERM 时停止接受新的请求，并等待已有请求完成
+type gracefulServer struct {
+	*http.Server
+}
+
 // initServer 函数初始化一个标准的 HTTP 服务器（适用于 Windows 系统）
 func initServer(address string, router *gin.Engine) server {
-	return &http.Server{
+	return gracefulServer{&http.Server{
 		Addr:           address,          // 设置服务器监听的地址
 		Handler:        router,           // 设置请求处理器（路由）
 		ReadTimeout:    10 * time.Minute, // 设置请求的读取超时时间为 10 分钟
 		WriteTimeout:   10 * time.Minute, // 设置响应的写入超时时间为 10 分钟
 		MaxHeaderBytes: 1 << 20,          // 设置最大请求头的大小（1MB）
+	}}
+}
+
+// ListenAndServe 启动服务器，收到退出信号并关闭完成后返回
+func (s gracefulServer) ListenAndServe() error {
+	done := make(chan error, 1)
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		<-quit
+		done <- s.Shutdown(context.Background())
+	}()
+
+	if err := s.Server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
+	return <-done
 }
@@ -0,0 +1,94 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// 事件的主题，RabbitMQ 实现中对应同名的队列
+const (
+	TopicESUpdate   = "es_update_queue"   // 更新 Elasticsearch 中文章浏览量、评论量和收藏量
+	TopicUserExport = "user_export_queue" // 用户数据导出任务
+)
+
+var (
+	ErrClosed     = errors.New("event bus is closed")
+	ErrBufferFull = errors.New("event bus buffer is full, the event was dropped")
+)
+
+// Bus 事件总线接口，发布者和订阅者不依赖具体的消息中间件
+type Bus interface {
+	// Publish 发布事件到主题
+	Publish(topic string, payload []byte) error
+	// Subscribe 订阅主题，handler 每次收到一批事件，需要对每条事件调用 Ack 或 Fail
+	Subscribe(topic string, opts SubscribeOptions, handler Handler)
+	// Close 停止订阅者并等待它们处理完已经收到的事件，然后关闭发布端，可以重复调用
+	Close()
+}
+
+// Message 订阅者收到的一条事件
+type Message interface {
+	// Body 事件内容
+	Body() []byte
+	// Ack 处理成功
+	Ack()
+	// Fail 处理失败，retryable 为 true 且订阅开启了重试时稍后重新投递，否则进入死信
+	Fail(reason string, retryable bool)
+}
+
+// Handler 处理一批事件
+type Handler func(msgs []Message)
+
+// SubscribeOptions 订阅选项
+type SubscribeOptions struct {
+	Name          string        // 订阅者名称
+	BatchSize     int           // 每批最多的事件数量
+	FlushInterval time.Duration // 批次未满时最长等待的时间
+	Retry         bool          // 处理失败的事件是否按退避间隔重试，不重试时直接丢弃
+}
+
+// ErrInputClosed 批量读取的通道已经关闭
+var ErrInputClosed = errors.New("input channel closed")
+
+// RunBatches 从 in 中读取事件，攒够 size 条或距离上次处理超过 interval 时调用 flush；
+// ctx 取消时处理完已经读取的事件后返回 nil，in 关闭时返回 ErrInputClosed，此时已读取的事件不会处理
+func RunBatches[T any](ctx context.Context, in <-chan T, size int, interval time.Duration, flush func([]T)) error {
+	size = max(size, 1)
+	if interval <= 0 {
+		interval = time.Second
+	}
+	var batch []T
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if len(batch) > 0 {
+				flush(batch)
+			}
+			return nil
+
+		case v, ok := <-in:
+			if !ok {
+				return ErrInputClosed
+			}
+			batch = append(batch, v)
+
+			// 达到批量大小立即处理
+			if len(batch) >= size {
+				flush(batch)
+				batch = nil
+				ticker.Reset(interval)
+			}
+
+		case <-ticker.C:
+			if len(batch) > 0 {
+				flush(batch)
+				batch = nil
+			}
+		}
+	}
+}
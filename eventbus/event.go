@@ -0,0 +1,13 @@
+package eventbus
+
+// ESEvent 更新 Elasticsearch 中文章计数的事件，发布到 TopicESUpdate
+type ESEvent struct {
+	ArticleID string `json:"article_id"` // 文章ID
+	Field     string `json:"field"`      // 更新字段（views/comments/likes）
+	Delta     int    `json:"delta"`      // 变化量（+1/-1）
+}
+
+// UserExportEvent 用户数据导出任务，发布到 TopicUserExport
+type UserExportEvent struct {
+	UserID uint `json:"user_id"` // 用户ID
+}
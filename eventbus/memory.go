@@ -0,0 +1,144 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MemoryBus 在进程内通过通道传递事件，不需要消息中间件，适合开发和测试。
+// 事件只保存在内存中，进程退出时未处理的事件和等待重试的事件会丢失
+type MemoryBus struct {
+	log         *zap.Logger
+	bufferSize  int
+	retryDelays []time.Duration
+
+	mu     sync.Mutex
+	topics map[string]chan *memoryMessage
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// memoryMessage 进程内传递的一条事件
+type memoryMessage struct {
+	bus      *MemoryBus
+	topic    string
+	body     []byte
+	attempts int  // 已经处理失败的次数
+	retry    bool // 订阅是否开启了重试
+}
+
+// NewMemoryBus 创建进程内的事件总线，bufferSize 为每个主题最多缓存的事件数量
+func NewMemoryBus(bufferSize int, retryDelays []time.Duration, log *zap.Logger) *MemoryBus {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MemoryBus{
+		log:         log,
+		bufferSize:  max(bufferSize, 1),
+		retryDelays: retryDelays,
+		topics:      make(map[string]chan *memoryMessage),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Publish 把事件放入主题的缓存，缓存已满时返回错误
+func (b *MemoryBus) Publish(topic string, payload []byte) error {
+	return b.publish(&memoryMessage{bus: b, topic: topic, body: payload})
+}
+
+// Subscribe 启动订阅者，同一主题的多个订阅者共同消费主题中的事件
+func (b *MemoryBus) Subscribe(topic string, opts SubscribeOptions, handler Handler) {
+	in := b.topic(topic)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		flush := func(batch []*memoryMessage) {
+			msgs := make([]Message, 0, len(batch))
+			for _, msg := range batch {
+				msg.retry = opts.Retry
+				msgs = append(msgs, msg)
+			}
+			handler(msgs)
+		}
+		_ = RunBatches(b.ctx, in, opts.BatchSize, opts.FlushInterval, flush)
+
+		// 关闭时处理完主题中缓存的事件
+		for {
+			batch := make([]*memoryMessage, 0, max(opts.BatchSize, 1))
+		drain:
+			for len(batch) < cap(batch) {
+				select {
+				case msg := <-in:
+					batch = append(batch, msg)
+				default:
+					break drain
+				}
+			}
+			if len(batch) == 0 {
+				return
+			}
+			flush(batch)
+		}
+	}()
+}
+
+// Close 停止订阅者，等待它们处理完缓存中的事件
+func (b *MemoryBus) Close() {
+	b.closeOnce.Do(func() {
+		b.cancel()
+		b.wg.Wait()
+	})
+}
+
+// publish 把事件放入主题的缓存
+func (b *MemoryBus) publish(msg *memoryMessage) error {
+	select {
+	case <-b.ctx.Done():
+		return ErrClosed
+	default:
+	}
+	select {
+	case b.topic(msg.topic) <- msg:
+		return nil
+	default:
+		return ErrBufferFull
+	}
+}
+
+// topic 返回主题的缓存，不存在时创建
+func (b *MemoryBus) topic(name string) chan *memoryMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch, ok := b.topics[name]
+	if !ok {
+		ch = make(chan *memoryMessage, b.bufferSize)
+		b.topics[name] = ch
+	}
+	return ch
+}
+
+func (m *memoryMessage) Body() []byte {
+	return m.body
+}
+
+func (m *memoryMessage) Ack() {}
+
+// Fail 按退避间隔重新放入主题，不可重试或重试次数用完时记录日志后丢弃
+func (m *memoryMessage) Fail(reason string, retryable bool) {
+	b := m.bus
+	if !m.retry || !retryable || m.attempts >= len(b.retryDelays) {
+		b.log.Error("Dropped event", zap.String("topic", m.topic), zap.Int("attempts", m.attempts), zap.String("reason", reason), zap.ByteString("body", m.body))
+		return
+	}
+	retry := &memoryMessage{bus: b, topic: m.topic, body: m.body, attempts: m.attempts + 1}
+	time.AfterFunc(b.retryDelays[m.attempts], func() {
+		if err := b.publish(retry); err != nil {
+			b.log.Error("Failed to retry event", zap.String("topic", retry.topic), zap.ByteString("body", retry.body), zap.Error(err))
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package flag
+
+import (
+	"fmt"
+	"server/model/request"
+	"server/service"
+)
+
+// DeadLetters 打印 ES 更新死信队列中最早的 100 条消息，返回死信队列中的消息总数
+func DeadLetters() (int, error) {
+	list, total, err := service.ServiceGroupApp.QueueService.DeadLetterList(request.DeadLetterList{Limit: 100})
+	if err != nil {
+		return 0, err
+	}
+	for _, letter := range list {
+		fmt.Printf("[%s] attempts=%d error=%q body=%s\n", letter.DeadLetteredAt.Format("2006-01-02 15:04:05"), letter.Attempts, letter.Error, letter.Body)
+	}
+	return total, nil
+}
+
+// DeadLetterReplay 把 ES 更新死信队列中最早的 limit 条消息重新发布到原队列
+func DeadLetterReplay(limit int) (int, error) {
+	if limit <= 0 {
+		return 0, fmt.Errorf("the number of messages to replay must be positive")
+	}
+	return service.ServiceGroupApp.QueueService.DeadLetterReplay(request.DeadLetterReplay{Limit: limit})
+}
@@ -35,6 +35,22 @@ var (
 		Name:  "es-import",
 		Usage: "Imports data into Elasticsearch from a specified file.",
 	}
+	esReconcileFlag = &cli.BoolFlag{
+		Name:  "es-reconcile",
+		Usage: "Recomputes article likes and comments in Elasticsearch from MySQL.",
+	}
+	esAuthorBackfillFlag = &cli.StringFlag{
+		Name:  "es-backfill-author",
+		Usage: "Sets the author of articles created before author follows to the user with the specified email.",
+	}
+	deadLetterFlag = &cli.BoolFlag{
+		Name:  "dead-letter",
+		Usage: "Prints the oldest messages in the ES update dead-letter queue without removing them.",
+	}
+	deadLetterReplayFlag = &cli.IntFlag{
+		Name:  "dead-letter-replay",
+		Usage: "Republishes up to the specified number of messages from the ES update dead-letter queue.",
+	}
 	adminFlag = &cli.BoolFlag{
 		Name:  "admin",
 		Usage: "Creates an administrator using the name, email and address specified in the config.yaml file.",
@@ -95,6 +111,30 @@ func Run(c *cli.Context) {
 		} else {
 			global.Log.Info(fmt.Sprintf("Successfully imported ES data, totaling %d records", num))
 		}
+	case c.Bool(esReconcileFlag.Name):
+		if num, err := ElasticsearchReconcile(); err != nil {
+			global.Log.Error("Failed to reconcile ES article counters:", zap.Error(err))
+		} else {
+			global.Log.Info(fmt.Sprintf("Successfully reconciled ES article counters, corrected %d discrepancies", num))
+		}
+	case c.IsSet(esAuthorBackfillFlag.Name):
+		if num, err := ElasticsearchAuthorBackfill(c.String(esAuthorBackfillFlag.Name)); err != nil {
+			global.Log.Error(fmt.Sprintf("Failed to backfill article authors after updating %d articles:", num), zap.Error(err))
+		} else {
+			global.Log.Info(fmt.Sprintf("Successfully backfilled article authors, updated %d articles", num))
+		}
+	case c.Bool(deadLetterFlag.Name):
+		if total, err := DeadLetters(); err != nil {
+			global.Log.Error("Failed to inspect dead letters:", zap.Error(err))
+		} else {
+			global.Log.Info(fmt.Sprintf("Successfully inspected dead letters, totaling %d messages", total))
+		}
+	case c.IsSet(deadLetterReplayFlag.Name):
+		if num, err := DeadLetterReplay(c.Int(deadLetterReplayFlag.Name)); err != nil {
+			global.Log.Error(fmt.Sprintf("Failed to replay dead letters after replaying %d messages:", num), zap.Error(err))
+		} else {
+			global.Log.Info(fmt.Sprintf("Successfully replayed %d dead letters", num))
+		}
 	case c.Bool(adminFlag.Name):
 		if err := Admin(); err != nil {
 			global.Log.Error("Failed to create an administrator:", zap.Error(err))
@@ -118,6 +158,10 @@ func NewApp() *cli.App {
 		esFlag,
 		esExportFlag,
 		esImportFlag,
+		esReconcileFlag,
+		esAuthorBackfillFlag,
+		deadLetterFlag,
+		deadLetterReplayFlag,
 		adminFlag,
 	}
 	app.Action = Run
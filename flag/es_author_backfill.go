@@ -0,0 +1,10 @@
+package flag
+
+import (
+	"server/service"
+)
+
+// ElasticsearchAuthorBackfill 把没有作者的文章的作者设置为指定邮箱的用户，返回更新的文章数量
+func ElasticsearchAuthorBackfill(email string) (int64, error) {
+	return service.ServiceGroupApp.ArticleService.ArticleAuthorBackfill(email)
+}
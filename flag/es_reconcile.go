@@ -0,0 +1,18 @@
+package flag
+
+import (
+	"fmt"
+	"server/service"
+)
+
+// ElasticsearchReconcile 根据 MySQL 重新计算 ES 中文章的收藏量和评论量，并打印每一处被纠正的差异
+func ElasticsearchReconcile() (int, error) {
+	discrepancies, err := service.ServiceGroupApp.ArticleService.ArticleCounterReconcile()
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range discrepancies {
+		fmt.Printf("article %s: %s %d -> %d\n", d.ArticleID, d.Field, d.Old, d.New)
+	}
+	return len(discrepancies), nil
+}
@@ -12,13 +12,25 @@ func SQL() error {
 		&database.ArticleCategory{},
 		&database.ArticleLike{},
 		&database.ArticleTag{},
+		&database.BookmarkFolder{},
 		&database.Comment{},
 		&database.Feedback{},
+		&database.Follow{},
 		&database.FooterLink{},
 		&database.FriendLink{},
 		&database.Image{},
 		&database.JwtBlacklist{},
+		&database.JwtKey{},
 		&database.Login{},
+		&database.Permission{},
+		&database.PersonalAccessToken{},
+		&database.ReadingHistory{},
+		&database.Role{},
 		&database.User{},
+		&database.UserIdentity{},
+		&database.UserRole{},
+		&database.UserTwoFactor{},
+		&database.RecoveryCode{},
+		&database.Session{},
 	)
 }
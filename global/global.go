@@ -2,6 +2,7 @@ package global
 
 import (
 	"server/config"
+	"server/eventbus"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/go-redis/redis"
@@ -20,4 +21,5 @@ var (
 	ESClient   *elasticsearch.TypedClient
 	BlackCache local_cache.Cache
 	RmqConn    *amqp091.Connection
+	EventBus   eventbus.Bus
 )
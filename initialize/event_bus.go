@@ -0,0 +1,21 @@
+package initialize
+
+import (
+	"server/eventbus"
+	"server/global"
+	"server/rabbitmq"
+	"server/utils"
+)
+
+// InitEventBus 根据配置中的事件总线类型创建事件总线，使用 RabbitMQ 时同时建立连接并初始化发布者
+func InitEventBus() eventbus.Bus {
+	cfg := global.Config.EventBus
+	switch cfg.Type {
+	case "memory":
+		return eventbus.NewMemoryBus(cfg.BufferSize, utils.ParseRetryDelays(cfg.RetryDelays), global.Log)
+	default:
+		global.RmqConn = RabbitmqInit()
+		rabbitmq.InitPublisher()
+		return rabbitmq.NewBus()
+	}
+}
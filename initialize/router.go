@@ -40,6 +40,7 @@ func InitRouter() *gin.Engine {
 		routerGroup.InitArticleRouter(privateGroup, publicGroup, adminGroup)
 		routerGroup.InitCommentRouter(privateGroup, publicGroup, adminGroup)
 		routerGroup.InitFeedbackRouter(privateGroup, publicGroup, adminGroup)
+		routerGroup.InitFollowRouter(privateGroup, publicGroup)
 	}
 	{
 		routerGroup.InitImageRouter(adminGroup)
@@ -47,6 +48,8 @@ func InitRouter() *gin.Engine {
 		routerGroup.InitFriendLinkRouter(adminGroup, publicGroup)
 		routerGroup.InitWebsiteRouter(adminGroup, publicGroup)
 		routerGroup.InitConfigRouter(adminGroup)
+		routerGroup.InitRBACRouter(adminGroup)
+		routerGroup.InitQueueRouter(adminGroup)
 	}
 	return Router
 }
@@ -2,10 +2,12 @@ package main
 
 import (
 	"server/core"
+	"server/eventbus"
 	"server/flag"
 	"server/global"
 	"server/initialize"
-	"server/rabbitmq"
+	"server/service"
+	"time"
 )
 
 func main() {
@@ -16,14 +18,22 @@ func main() {
 	global.Redis = initialize.ConnectRedis()
 	global.ESClient = initialize.ConnectEs()
 	defer global.Redis.Close()
-	global.RmqConn = initialize.RabbitmqInit()
-	defer global.RmqConn.Close()
+	global.EventBus = initialize.InitEventBus()
 
 	flag.InitFlag()
 
 	initialize.InitCron()
 
-	go rabbitmq.StartESUpdateConsumer()
+	global.EventBus.Subscribe(eventbus.TopicESUpdate, eventbus.SubscribeOptions{
+		Name:          "es_update",
+		BatchSize:     100,
+		FlushInterval: 5 * time.Second,
+		Retry:         true,
+	}, service.ServiceGroupApp.ArticleService.HandleESUpdates)
+	global.EventBus.Subscribe(eventbus.TopicUserExport, eventbus.SubscribeOptions{
+		Name:      "user_export",
+		BatchSize: 1,
+	}, service.ServiceGroupApp.UserService.HandleUserExports)
 
 	core.RunServer()
 }
@@ -2,20 +2,48 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
-	"server/model/appTypes"
+	"server/global"
 	"server/model/response"
+	"server/service"
 	"server/utils"
+
+	"go.uber.org/zap"
 )
 
+// AdminAuth 只允许拥有管理权限的用户访问管理接口，具体的权限由各路由的 Permission 中间件校验
 func AdminAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		roleID := utils.GetRoleID(c)
-
-		if roleID != appTypes.Admin {
+		permissions, err := service.ServiceGroupApp.RBACService.UserPermissions(utils.GetUserID(c))
+		if err != nil {
+			global.Log.Error("Failed to get user permissions:", zap.Error(err))
 			response.Forbidden("Access denied. Admin privileges are required", c)
 			c.Abort()
 			return
 		}
+		if len(permissions) == 0 {
+			response.Forbidden("Access denied. Admin privileges are required", c)
+			c.Abort()
+			return
+		}
+
+		// 开启了管理员强制两步验证时，未启用两步验证的管理员无法访问管理接口
+		if global.Config.System.RequireAdmin2FA && !service.ServiceGroupApp.UserService.TwoFactorEnabled(utils.GetUserID(c)) {
+			response.Forbidden("Access denied. Two-factor authentication is required for administrators", c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Permission 只允许拥有指定权限的用户访问
+func Permission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !service.ServiceGroupApp.RBACService.HasPermission(utils.GetUserID(c), permission) {
+			response.Forbidden("Access denied. The "+permission+" permission is required", c)
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"server/service"
 	"server/utils"
 	"strconv"
+	"time"
 )
 
 var jwtService = service.ServiceGroupApp.JwtService
@@ -17,6 +18,12 @@ var jwtService = service.ServiceGroupApp.JwtService
 // JWTAuth 是一个中间件函数，验证请求中的JWT token是否合法
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 使用个人访问令牌的请求不走 Access Token 和 Refresh Token 的校验
+		if token := utils.GetBearerToken(c); service.IsPersonalAccessToken(token) {
+			personalAccessTokenAuth(c, token)
+			return
+		}
+
 		// 获取请求中的Access Token和Refresh Token
 		accessToken := utils.GetAccessToken(c)
 		refreshToken := utils.GetRefreshToken(c)
@@ -47,6 +54,15 @@ func JWTAuth() gin.HandlerFunc {
 					return
 				}
 
+				// 轮换Refresh Token，会话已被下线或旧令牌被重复使用时拒绝刷新
+				newRefreshToken, newRefreshClaims, err := rotateRefreshToken(c, refreshToken, refreshClaims)
+				if err != nil {
+					utils.ClearRefreshToken(c)
+					response.NoAuth("The session has been revoked", c)
+					c.Abort()
+					return
+				}
+
 				// 如果Refresh Token有效，通过其UserID获取用户信息
 				var user database.User
 				if err := global.DB.Select("uuid", "role_id").Take(&user, refreshClaims.UserID).Error; err != nil {
@@ -73,6 +89,11 @@ func JWTAuth() gin.HandlerFunc {
 					return
 				}
 
+				// 设置新的Refresh Token
+				if newRefreshToken != "" {
+					utils.SetRefreshToken(c, newRefreshToken, int(newRefreshClaims.ExpiresAt.Unix()-time.Now().Unix()))
+				}
+
 				// 将新的Access Token和过期时间添加到响应头中
 				c.Header("new-access-token", newAccessToken)
 				c.Header("new-access-expires-at", strconv.FormatInt(newAccessClaims.ExpiresAt.Unix(), 10))
@@ -95,3 +116,25 @@ func JWTAuth() gin.HandlerFunc {
 		c.Next() // 继续后续的处理
 	}
 }
+
+// rotateRefreshToken 轮换Refresh Token，旧版本签发的不含会话标识的Refresh Token会被迁移为新的会话
+func rotateRefreshToken(c *gin.Context, refreshToken string, refreshClaims *request.JwtCustomRefreshClaims) (string, *request.JwtCustomRefreshClaims, error) {
+	sessionService := service.ServiceGroupApp.SessionService
+	if refreshClaims.ID != "" {
+		return sessionService.RotateSession(refreshClaims, c.ClientIP())
+	}
+
+	j := utils.NewJWT()
+	newRefreshClaims := j.CreateRefreshClaims(request.BaseClaims{UserID: refreshClaims.UserID})
+	newRefreshToken, err := j.CreateRefreshToken(newRefreshClaims)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := sessionService.CreateSession(refreshClaims.UserID, newRefreshToken, newRefreshClaims, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		return "", nil, err
+	}
+	if err := jwtService.JoinInBlacklist(database.JwtBlacklist{Jwt: refreshToken}); err != nil {
+		return "", nil, err
+	}
+	return newRefreshToken, &newRefreshClaims, nil
+}
@@ -2,11 +2,12 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/ua-parser/uap-go/uaparser"
 	"go.uber.org/zap"
+	"net/http"
 	"server/global"
 	"server/model/database"
 	"server/service"
+	"server/utils"
 )
 
 // LoginRecord 是一个中间件，用于记录登录日志
@@ -30,11 +31,19 @@ func LoginRecord() gin.HandlerFunc {
 				}
 			}
 
+			// 登录接口可以通过 login_status 指定登录状态，例如密码错误或账户被锁定，否则使用响应的状态码
+			status := c.Writer.Status()
+			if value, exists := c.Get("login_status"); exists {
+				if s, ok := value.(int); ok {
+					status = s
+				}
+			}
+
 			// 获取用户IP的地理位置
-			address = getAddressFromIP(ip, gaodeService)
+			address = gaodeService.GetAddressByIP(ip)
 
 			// 解析用户的浏览器、操作系统和设备信息
-			os, deviceInfo, browserInfo := parseUserAgent(userAgent)
+			os, deviceInfo, browserInfo := utils.ParseUserAgent(userAgent)
 
 			// 创建登录记录
 			login := database.Login{
@@ -45,40 +54,21 @@ func LoginRecord() gin.HandlerFunc {
 				OS:          os,
 				DeviceInfo:  deviceInfo,
 				BrowserInfo: browserInfo,
-				Status:      c.Writer.Status(),
+				Status:      status,
 			}
 
 			// 将登录记录存储到数据库
 			if err := global.DB.Create(&login).Error; err != nil {
 				global.Log.Error("Failed to record login", zap.Error(err))
+				return
 			}
-		}()
-	}
-}
 
-// 获取IP地址对应的地理位置信息
-func getAddressFromIP(ip string, gaodeService service.GaodeService) string {
-	res, err := gaodeService.GetLocationByIP(ip)
-	if err != nil || res.Province == "" {
-		return "未知"
-	}
-	if res.City != "" && res.Province != res.City {
-		return res.Province + "-" + res.City
+			// 登录成功时检查是否为新的设备或城市，并提醒用户
+			if userID != 0 && status == http.StatusOK {
+				if err := service.ServiceGroupApp.UserService.LoginAlert(login); err != nil {
+					global.Log.Error("Failed to send login alert", zap.Error(err))
+				}
+			}
+		}()
 	}
-	return res.Province
-}
-
-// 解析用户代理（User-Agent）字符串，提取操作系统、设备信息和浏览器信息
-func parseUserAgent(userAgent string) (os, deviceInfo, browserInfo string) {
-	os = userAgent
-	deviceInfo = userAgent
-	browserInfo = userAgent
-
-	parser := uaparser.NewFromSaved()
-	cli := parser.Parse(userAgent)
-	os = cli.Os.Family
-	deviceInfo = cli.Device.Family
-	browserInfo = cli.UserAgent.Family
-
-	return
 }
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"math"
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+	"server/service"
+	"server/utils"
+	"strconv"
+)
+
+// RateLimit 是一个中间件，按照配置文件中 route 对应的规则限制请求频率。
+// 已登录的请求按用户 UUID 计数，未登录的请求按客户端 IP 计数。
+func RateLimit(route string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rateLimitCfg := global.Config.RateLimit
+		rule, ok := rateLimitCfg.Routes[route]
+		if !rateLimitCfg.Enable || !ok || rule.Limit <= 0 {
+			c.Next()
+			return
+		}
+
+		window, err := utils.ParseDuration(rule.Window)
+		if err != nil {
+			global.Log.Error("Invalid rate limit window", zap.String("route", route), zap.Error(err))
+			c.Next()
+			return
+		}
+
+		// 优先使用 JWTAuth 中间件解析出的用户信息，否则退回到客户端 IP
+		identity := "ip:" + c.ClientIP()
+		if claims, exists := c.Get("claims"); exists {
+			identity = "user:" + claims.(*request.JwtCustomClaims).UUID.String()
+		}
+
+		allowed, retryAfter, err := service.ServiceGroupApp.RateLimitService.Allow(route+":"+identity, rule.Limit, window)
+		if err != nil {
+			// Redis 不可用时不阻断正常请求
+			global.Log.Error("Failed to check rate limit", zap.String("route", route), zap.Error(err))
+			c.Next()
+			return
+		}
+		if !allowed {
+			global.Log.Warn("Rate limit exceeded",
+				zap.String("route", route),
+				zap.String("identity", identity),
+				zap.String("path", c.Request.URL.Path),
+				zap.Duration("retry_after", retryAfter),
+			)
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			response.TooManyRequests("Too many requests, please try again later", c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
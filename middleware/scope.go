@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+	"server/service"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// scopedRoutes 允许个人访问令牌访问的路由，键为 "请求方法 完整路径"，值为令牌需要具有的权限范围，在注册路由时写入
+var scopedRoutes sync.Map
+
+// ScopedGroup 允许个人访问令牌访问的路由组，通过它注册的路由要求个人访问令牌具有指定的权限范围，普通的登录用户不受影响
+type ScopedGroup struct {
+	group *gin.RouterGroup
+	scope string
+}
+
+// Scoped 在路由组上声明允许个人访问令牌使用的权限范围
+func Scoped(group *gin.RouterGroup, scope string) ScopedGroup {
+	return ScopedGroup{group: group, scope: scope}
+}
+
+// Handle 注册路由，并记录该路由允许个人访问令牌访问
+func (g ScopedGroup) Handle(httpMethod, relativePath string, handlers ...gin.HandlerFunc) {
+	fullPath := path.Join(g.group.BasePath(), relativePath)
+	// 与 gin 拼接路径的方式保持一致，保留末尾的斜杠
+	if relativePath != "" && relativePath[len(relativePath)-1] == '/' && fullPath[len(fullPath)-1] != '/' {
+		fullPath += "/"
+	}
+	scopedRoutes.Store(httpMethod+" "+fullPath, g.scope)
+	g.group.Handle(httpMethod, relativePath, handlers...)
+}
+
+func (g ScopedGroup) GET(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+func (g ScopedGroup) POST(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+func (g ScopedGroup) PUT(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPut, relativePath, handlers...)
+}
+
+func (g ScopedGroup) DELETE(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodDelete, relativePath, handlers...)
+}
+
+// personalAccessTokenAuth 校验个人访问令牌，只有通过 ScopedGroup 注册的路由才接受个人访问令牌
+func personalAccessTokenAuth(c *gin.Context, token string) {
+	value, scoped := scopedRoutes.Load(c.Request.Method + " " + c.FullPath())
+	if !scoped {
+		response.Forbidden("Access denied. Personal access tokens cannot be used on this route", c)
+		c.Abort()
+		return
+	}
+	scope := value.(string)
+
+	user, scopes, err := service.ServiceGroupApp.PersonalAccessTokenService.TokenAuth(token, c.ClientIP())
+	if err != nil {
+		if !errors.Is(err, service.ErrInvalidPersonalAccessToken) {
+			global.Log.Error("Failed to authenticate personal access token:", zap.Error(err))
+		}
+		response.NoAuth(err.Error(), c)
+		c.Abort()
+		return
+	}
+
+	allowed := false
+	for _, s := range scopes {
+		if s == scope {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		response.Forbidden("Access denied. The personal access token requires the "+scope+" scope", c)
+		c.Abort()
+		return
+	}
+
+	c.Set("claims", &request.JwtCustomClaims{BaseClaims: request.BaseClaims{
+		UserID: user.ID,
+		UUID:   user.UUID,
+		RoleID: user.RoleID,
+	}})
+	c.Next()
+}
@@ -0,0 +1,62 @@
+package appTypes
+
+import "encoding/json"
+
+// CommentStatus 评论状态
+type CommentStatus int
+
+const (
+	Published  CommentStatus = iota // 已发布
+	Pending                         // 待审核
+	Unverified                      // 待邮箱验证
+	Rejected                        // 审核未通过
+)
+
+// MarshalJSON 实现了 json.Marshaler 接口
+func (s CommentStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON 实现了 json.Unmarshaler 接口
+func (s *CommentStatus) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*s = ToCommentStatus(str)
+	return nil
+}
+
+// String 方法返回 CommentStatus 的字符串表示
+func (s CommentStatus) String() string {
+	var str string
+	switch s {
+	case Published:
+		str = "已发布"
+	case Pending:
+		str = "待审核"
+	case Unverified:
+		str = "待验证"
+	case Rejected:
+		str = "未通过"
+	default:
+		str = "未知状态"
+	}
+	return str
+}
+
+// ToCommentStatus 函数将字符串转换为 CommentStatus
+func ToCommentStatus(str string) CommentStatus {
+	switch str {
+	case "已发布":
+		return Published
+	case "待审核":
+		return Pending
+	case "待验证":
+		return Unverified
+	case "未通过":
+		return Rejected
+	default:
+		return -1
+	}
+}
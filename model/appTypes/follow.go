@@ -0,0 +1,15 @@
+package appTypes
+
+// FollowType 关注的对象类型
+type FollowType string
+
+const (
+	FollowUser     FollowType = "user"     // 关注用户，对象为用户的 uuid
+	FollowTag      FollowType = "tag"      // 关注标签，对象为标签名称
+	FollowCategory FollowType = "category" // 关注类别，对象为类别名称
+)
+
+// IsValid 判断关注的对象类型是否存在
+func (t FollowType) IsValid() bool {
+	return t == FollowUser || t == FollowTag || t == FollowCategory
+}
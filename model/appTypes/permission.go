@@ -0,0 +1,42 @@
+package appTypes
+
+// 权限名称，路由通过 middleware.Permission 校验当前用户是否拥有对应的权限
+const (
+	PermissionArticleManage       = "article:manage"       // 管理文章
+	PermissionCommentManage       = "comment:manage"       // 管理评论
+	PermissionFeedbackManage      = "feedback:manage"      // 管理反馈
+	PermissionUserManage          = "user:manage"          // 管理用户
+	PermissionImageManage         = "image:manage"         // 管理图片
+	PermissionAdvertisementManage = "advertisement:manage" // 管理广告
+	PermissionFriendLinkManage    = "friend_link:manage"   // 管理友链
+	PermissionWebsiteManage       = "website:manage"       // 管理网站轮播图和页脚链接
+	PermissionConfigManage        = "config:manage"        // 管理系统配置
+	PermissionRoleManage          = "role:manage"          // 管理角色和权限分配
+	PermissionQueueManage         = "queue:manage"         // 查看消费者状态，查看和重放消息队列中的死信
+)
+
+// Permissions 所有的权限及其描述，启动时同步到权限表
+var Permissions = []struct {
+	Name        string
+	Description string
+}{
+	{PermissionArticleManage, "管理文章"},
+	{PermissionCommentManage, "管理评论"},
+	{PermissionFeedbackManage, "管理反馈"},
+	{PermissionUserManage, "管理用户"},
+	{PermissionImageManage, "管理图片"},
+	{PermissionAdvertisementManage, "管理广告"},
+	{PermissionFriendLinkManage, "管理友链"},
+	{PermissionWebsiteManage, "管理网站轮播图和页脚链接"},
+	{PermissionConfigManage, "管理系统配置"},
+	{PermissionRoleManage, "管理角色和权限分配"},
+	{PermissionQueueManage, "查看消费者状态，查看和重放消息队列中的死信"},
+}
+
+// 内置角色的名称
+const (
+	RoleAdmin     = "admin"     // 管理员，拥有所有权限
+	RoleEditor    = "editor"    // 编辑，管理文章和图片
+	RoleModerator = "moderator" // 审核员，管理评论和反馈
+	RoleUser      = "user"      // 普通用户，没有管理权限
+)
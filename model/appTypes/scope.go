@@ -0,0 +1,30 @@
+package appTypes
+
+// 个人访问令牌的权限范围，路由通过 middleware.Scope 声明允许使用的范围，没有声明的路由不接受个人访问令牌
+const (
+	ScopeUserRead     = "user:read"     // 读取个人信息
+	ScopeArticleWrite = "article:write" // 创建、修改和删除文章，还需要账户拥有管理文章的权限
+	ScopeImageUpload  = "image:upload"  // 上传图片，还需要账户拥有管理图片的权限
+	ScopeCommentWrite = "comment:write" // 发表和删除评论
+)
+
+// Scopes 所有的权限范围及其描述
+var Scopes = []struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}{
+	{ScopeUserRead, "读取个人信息"},
+	{ScopeArticleWrite, "创建、修改和删除文章"},
+	{ScopeImageUpload, "上传图片"},
+	{ScopeCommentWrite, "发表和删除评论"},
+}
+
+// IsValidScope 判断权限范围是否存在
+func IsValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s.Name == scope {
+			return true
+		}
+	}
+	return false
+}
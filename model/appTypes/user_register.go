@@ -6,8 +6,11 @@ import "encoding/json"
 type Register int
 
 const (
-	Email Register = iota // 邮箱验证码注册
-	QQ                    // QQ登录注册
+	Email  Register = iota // 邮箱验证码注册
+	QQ                     // QQ登录注册
+	Github                 // GitHub登录注册
+	OIDC                   // OpenID Connect登录注册
+	OAuth2                 // 其他OAuth2登录注册
 )
 
 // MarshalJSON 实现了 json.Marshaler 接口
@@ -33,12 +36,30 @@ func (r Register) String() string {
 		str = "邮箱"
 	case QQ:
 		str = "QQ"
+	case Github:
+		str = "GitHub"
+	case OIDC:
+		str = "OIDC"
+	case OAuth2:
+		str = "OAuth2"
 	default:
 		str = "未知"
 	}
 	return str
 }
 
+// ToOAuthRegister 函数将第三方登录提供方的类型转换为 Register
+func ToOAuthRegister(providerType string) Register {
+	switch providerType {
+	case "github":
+		return Github
+	case "oidc":
+		return OIDC
+	default:
+		return OAuth2
+	}
+}
+
 // ToRegister 函数将字符串转换为 Register
 func ToRegister(str string) Register {
 	switch str {
@@ -46,6 +67,12 @@ func ToRegister(str string) Register {
 		return Email
 	case "QQ":
 		return QQ
+	case "GitHub":
+		return Github
+	case "OIDC":
+		return OIDC
+	case "OAuth2":
+		return OAuth2
 	default:
 		return -1
 	}
@@ -8,4 +8,6 @@ type ArticleLike struct {
 	ArticleID string `json:"article_id"` // 文章 ID
 	UserID    uint   `json:"user_id"`    // 用户 ID
 	User      User   `json:"-" gorm:"foreignKey:UserID"`
+	FolderID  *uint  `json:"folder_id" gorm:"index"` // 收藏夹 ID，为空表示未分类
+	Note      string `json:"note" gorm:"size:500"`   // 私人备注，只有自己可见
 }
@@ -0,0 +1,12 @@
+package database
+
+import "server/global"
+
+// BookmarkFolder 收藏夹表，用户的收藏可以归入不同的收藏夹
+type BookmarkFolder struct {
+	global.MODEL
+	UserID      uint   `json:"user_id" gorm:"index"` // 用户 ID
+	User        User   `json:"-" gorm:"foreignKey:UserID"`
+	Name        string `json:"name" gorm:"size:50"` // 收藏夹名称
+	Description string `json:"description"`         // 描述
+}
@@ -2,8 +2,9 @@ package database
 
 import (
 	"encoding/json"
+	"server/eventbus"
 	"server/global"
-	"server/rabbitmq"
+	"server/model/appTypes"
 
 	"github.com/gofrs/uuid"
 	"gorm.io/gorm"
@@ -12,46 +13,53 @@ import (
 // Comment 评论表
 type Comment struct {
 	global.MODEL
-	ArticleID string    `json:"article_id"` // 文章 ID
-	PID       *uint     `json:"p_id"`       // 父评论 ID
-	PComment  *Comment  `json:"-" gorm:"foreignKey:PID"`
-	Children  []Comment `json:"children" gorm:"foreignKey:PID"`                  // 子评论
-	UserUUID  uuid.UUID `json:"user_uuid" gorm:"type:char(36)"`                  // 用户 uuid
-	User      User      `json:"user" gorm:"foreignKey:UserUUID;references:UUID"` // 关联的用户
-	Content   string    `json:"content"`                                         // 内容
+	ArticleID     string                 `json:"article_id"` // 文章 ID
+	PID           *uint                  `json:"p_id"`       // 父评论 ID
+	PComment      *Comment               `json:"-" gorm:"foreignKey:PID"`
+	Children      []Comment              `json:"children" gorm:"foreignKey:PID"`                  // 子评论
+	UserUUID      *uuid.UUID             `json:"user_uuid" gorm:"type:char(36)"`                  // 用户 uuid，游客和已注销用户的评论为 NULL
+	User          User                   `json:"user" gorm:"foreignKey:UserUUID;references:UUID"` // 关联的用户
+	GuestNickname string                 `json:"guest_nickname" gorm:"size:20"`                   // 游客昵称
+	GuestEmail    string                 `json:"-" gorm:"index"`                                  // 游客邮箱，用户注册后据此关联到账号
+	GuestVerified bool                   `json:"-"`                                               // 游客是否已通过邮件验证邮箱，只有验证过的评论才会关联到账号
+	Content       string                 `json:"content"`                                         // 内容
+	Status        appTypes.CommentStatus `json:"status" gorm:"default:0;index"`                   // 评论状态，只有已发布的评论会公开展示
 }
 
 // AfterCreate 钩子，创建后调用
 func (c *Comment) AfterCreate(_ *gorm.DB) error {
+	// 只有已发布的评论计入文章评论量，待审核的评论在审核通过时再计入
+	if c.Status != appTypes.Published {
+		return nil
+	}
 	// source := "ctx._source.comments += 1"
 	// script := types.Script{Source: &source, Lang: &scriptlanguage.Painless}
 	// _, err := global.ESClient.Update(elasticsearch.ArticleIndex(), c.ArticleID).Script(&script).Do(context.TODO())
-	event := rabbitmq.ESEvent{
+	event := eventbus.ESEvent{
 		ArticleID: c.ArticleID,
 		Field:     "comments",
 		Delta:     1,
 	}
 	msgBytes, _ := json.Marshal(event)
-	err := rabbitmq.PublishMessage(global.RmqConn, "es_update_queue", msgBytes)
+	err := global.EventBus.Publish(eventbus.TopicESUpdate, msgBytes)
 	return err
 }
 
-// AfterDelete 钩子，删除后调用
+// BeforeDelete 钩子，删除前调用，调用方需传入完整加载的评论
 func (c *Comment) BeforeDelete(_ *gorm.DB) error {
-	var articleID string
-	if err := global.DB.Model(&c).Pluck("article_id", &articleID).Error; err != nil {
-		return err
+	if c.Status != appTypes.Published {
+		return nil
 	}
 	// source := "ctx._source.comments -= 1"
 	// script := types.Script{Source: &source, Lang: &scriptlanguage.Painless}
 	// _, err := global.ESClient.Update(elasticsearch.ArticleIndex(), articleID).Script(&script).Do(context.TODO())
 
-	event := rabbitmq.ESEvent{
-		ArticleID: articleID,
+	event := eventbus.ESEvent{
+		ArticleID: c.ArticleID,
 		Field:     "comments",
 		Delta:     -1,
 	}
 	msgBytes, _ := json.Marshal(event)
-	err := rabbitmq.PublishMessage(global.RmqConn, "es_update_queue", msgBytes)
+	err := global.EventBus.Publish(eventbus.TopicESUpdate, msgBytes)
 	return err
 }
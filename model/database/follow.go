@@ -0,0 +1,16 @@
+package database
+
+import (
+	"server/model/appTypes"
+	"time"
+)
+
+// Follow 关注表，记录用户关注的作者、标签和类别
+type Follow struct {
+	ID         uint                `json:"id" gorm:"primary_key"`
+	UserID     uint                `json:"user_id" gorm:"uniqueIndex:idx_follow"` // 关注者的用户 ID
+	User       User                `json:"-" gorm:"foreignKey:UserID"`
+	TargetType appTypes.FollowType `json:"target_type" gorm:"size:16;uniqueIndex:idx_follow;index:idx_follow_target"` // 关注的对象类型
+	Target     string              `json:"target" gorm:"size:191;uniqueIndex:idx_follow;index:idx_follow_target"`     // 关注的对象：用户的 uuid、标签名称或类别名称
+	CreatedAt  time.Time           `json:"createdAt"`
+}
@@ -1,9 +1,13 @@
 package database
 
-import "server/global"
+import (
+	"server/global"
+	"time"
+)
 
 // JwtBlacklist JWT 黑名单表
 type JwtBlacklist struct {
 	global.MODEL
-	Jwt string `json:"jwt" gorm:"type:text"` // Jwt
+	Jwt       string     `json:"jwt" gorm:"type:text"`    // Jwt
+	ExpiresAt *time.Time `json:"expires_at" gorm:"index"` // Jwt 的过期时间，过期后记录会被定期清理
 }
@@ -0,0 +1,18 @@
+package database
+
+import (
+	"server/global"
+	"time"
+)
+
+// JwtKey JWT 签名密钥表
+type JwtKey struct {
+	global.MODEL
+	Kid        string     `json:"kid" gorm:"size:64;uniqueIndex"`          // 密钥 ID，写入 JWT 头部的 kid 字段
+	Use        string     `json:"use" gorm:"column:key_use;size:16;index"` // 用途："access" 或 "refresh"
+	Algorithm  string     `json:"algorithm" gorm:"size:16"`                // 签名算法："HS256"、"RS256" 或 "EdDSA"
+	PrivateKey string     `json:"-" gorm:"type:text"`                      // 私钥，HS256 为 Base64 编码的密钥，其他算法为 PKCS#8 PEM
+	PublicKey  string     `json:"-" gorm:"type:text"`                      // 公钥，PKIX PEM，HS256 为空
+	RetiredAt  *time.Time `json:"retired_at"`                              // 停止签发的时间，为空表示当前用于签发的密钥
+	ExpiresAt  *time.Time `json:"expires_at" gorm:"index"`                 // 停止校验的时间，停止签发后保留一段宽限期用于校验已签发的令牌
+}
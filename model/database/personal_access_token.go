@@ -0,0 +1,20 @@
+package database
+
+import (
+	"server/global"
+	"time"
+)
+
+// PersonalAccessToken 个人访问令牌表，用于自动化脚本以 Bearer Token 的方式调用接口
+type PersonalAccessToken struct {
+	global.MODEL
+	UserID     uint       `json:"user_id" gorm:"index"` // 用户 ID
+	User       User       `json:"-" gorm:"foreignKey:UserID"`
+	Name       string     `json:"name" gorm:"size:50"`                             // 令牌名称
+	TokenHash  string     `json:"-" gorm:"size:64;uniqueIndex"`                    // 令牌的 SHA-256 哈希，令牌明文只在创建时返回一次
+	Prefix     string     `json:"prefix" gorm:"size:16"`                           // 令牌的前几位，用于辨认令牌
+	Scopes     []string   `json:"scopes" gorm:"type:varchar(255);serializer:json"` // 权限范围
+	ExpiresAt  *time.Time `json:"expires_at"`                                      // 过期时间，为空表示永不过期
+	LastUsedAt *time.Time `json:"last_used_at"`                                    // 最近一次使用的时间
+	LastUsedIP string     `json:"last_used_ip"`                                    // 最近一次使用的 IP 地址
+}
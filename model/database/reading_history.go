@@ -0,0 +1,15 @@
+package database
+
+import "time"
+
+// ReadingHistory 阅读记录表，记录用户读过的文章和上次阅读的位置
+type ReadingHistory struct {
+	ID        uint      `json:"id" gorm:"primary_key"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_reading_history"` // 用户 ID
+	User      User      `json:"-" gorm:"foreignKey:UserID"`
+	ArticleID string    `json:"article_id" gorm:"size:64;uniqueIndex:idx_reading_history"` // 文章 ID
+	Progress  float64   `json:"progress"`                                                  // 阅读进度，0 到 1 之间的滚动比例
+	Position  string    `json:"position" gorm:"size:200"`                                  // 上次阅读到的标题锚点
+	ReadAt    time.Time `json:"read_at" gorm:"index"`                                      // 最后阅读时间
+	CreatedAt time.Time `json:"created_at"`                                                // 第一次阅读时间
+}
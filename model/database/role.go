@@ -0,0 +1,32 @@
+package database
+
+import (
+	"server/global"
+	"time"
+)
+
+// Role 角色表
+type Role struct {
+	global.MODEL
+	Name        string       `json:"name" gorm:"size:64;uniqueIndex"`               // 角色名称
+	DisplayName string       `json:"display_name"`                                  // 展示名称
+	Description string       `json:"description"`                                   // 描述
+	BuiltIn     bool         `json:"built_in"`                                      // 是否为内置角色，内置角色不能删除
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions"` // 角色拥有的权限
+}
+
+// Permission 权限表
+type Permission struct {
+	global.MODEL
+	Name        string `json:"name" gorm:"size:64;uniqueIndex"` // 权限名称，例如 "article:manage"
+	Description string `json:"description"`                     // 描述
+}
+
+// UserRole 用户角色表，没有分配角色的用户按 User.RoleID 使用对应的内置角色
+type UserRole struct {
+	ID        uint      `json:"id" gorm:"primary_key"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_user_role"` // 用户 ID
+	RoleID    uint      `json:"role_id" gorm:"uniqueIndex:idx_user_role"` // 角色 ID
+	Role      Role      `json:"role" gorm:"foreignKey:RoleID"`
+	CreatedAt time.Time `json:"createdAt"`
+}
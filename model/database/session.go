@@ -0,0 +1,21 @@
+package database
+
+import (
+	"server/global"
+	"time"
+)
+
+// Session 登录会话表，每个 Refresh Token 对应一条记录
+type Session struct {
+	global.MODEL
+	UserID       uint      `json:"user_id" gorm:"index"`         // 用户 ID
+	TokenID      string    `json:"-" gorm:"size:64;uniqueIndex"` // Refresh Token 的唯一标识（jti）
+	RefreshToken string    `json:"-" gorm:"type:text"`           // Refresh Token，撤销会话时加入黑名单
+	IP           string    `json:"ip"`                           // 登录时的 IP 地址
+	Address      string    `json:"address"`                      // 登录地址
+	OS           string    `json:"os"`                           // 操作系统
+	DeviceInfo   string    `json:"device_info"`                  // 设备信息
+	BrowserInfo  string    `json:"browser_info"`                 // 浏览器信息
+	LastActiveAt time.Time `json:"last_active_at"`               // 最近一次活跃时间，刷新 Access Token 时更新
+	ExpiresAt    time.Time `json:"expires_at" gorm:"index"`      // 过期时间，与 Refresh Token 的过期时间一致
+}
@@ -0,0 +1,14 @@
+package database
+
+import "server/global"
+
+// UserIdentity 用户第三方登录身份表，一个用户可以关联多个第三方身份
+type UserIdentity struct {
+	global.MODEL
+	UserID   uint   `json:"user_id" gorm:"index"` // 用户 ID
+	User     User   `json:"-" gorm:"foreignKey:UserID"`
+	Provider string `json:"provider" gorm:"size:64;uniqueIndex:idx_provider_subject"` // 登录提供方名称
+	Subject  string `json:"subject" gorm:"size:191;uniqueIndex:idx_provider_subject"` // 用户在提供方的唯一标识
+	Email    string `json:"email"`                                                    // 提供方返回的邮箱
+	Name     string `json:"name"`                                                     // 提供方返回的昵称
+}
@@ -0,0 +1,20 @@
+package database
+
+import "server/global"
+
+// UserTwoFactor 用户两步验证表
+type UserTwoFactor struct {
+	global.MODEL
+	UserID       uint   `json:"user_id" gorm:"uniqueIndex"` // 用户 ID
+	Secret       string `json:"-"`                          // TOTP 密钥
+	Enabled      bool   `json:"enabled"`                    // 是否已确认启用，登记后未确认时为 false
+	LastUsedStep int64  `json:"-"`                          // 最近一次使用的动态码时间步，防止同一动态码被重复使用
+}
+
+// RecoveryCode 两步验证恢复码表，每个恢复码只能使用一次
+type RecoveryCode struct {
+	global.MODEL
+	UserID   uint   `json:"user_id" gorm:"index"`   // 用户 ID
+	CodeHash string `json:"-" gorm:"size:64;index"` // 恢复码的 SHA-256 摘要
+	Used     bool   `json:"used"`                   // 是否已使用
+}
@@ -17,6 +17,8 @@ type Article struct {
 	Abstract string   `json:"abstract"` // 文章简介
 	Content  string   `json:"content"`  // 文章内容
 
+	AuthorUUID string `json:"author_uuid"` // 作者 uuid，用于关注作者的动态
+
 	Views    int `json:"views"`    // 浏览量
 	Comments int `json:"comments"` // 评论量
 	Likes    int `json:"likes"`    // 收藏量
@@ -31,18 +33,26 @@ func ArticleIndex() string {
 func ArticleMapping() *types.TypeMapping {
 	return &types.TypeMapping{
 		Properties: map[string]types.Property{
-			"created_at": types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
-			"updated_at": types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
-			"cover":      types.TextProperty{},
-			"title":      types.TextProperty{},
-			"keyword":    types.KeywordProperty{},
-			"category":   types.KeywordProperty{},
-			"tags":       []types.KeywordProperty{},
-			"abstract":   types.TextProperty{},
-			"content":    types.TextProperty{},
-			"views":      types.IntegerNumberProperty{},
-			"comments":   types.IntegerNumberProperty{},
-			"likes":      types.IntegerNumberProperty{},
+			"created_at":  types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
+			"updated_at":  types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
+			"cover":       types.TextProperty{},
+			"title":       types.TextProperty{},
+			"keyword":     types.KeywordProperty{},
+			"category":    types.KeywordProperty{},
+			"tags":        []types.KeywordProperty{},
+			"abstract":    types.TextProperty{},
+			"content":     types.TextProperty{},
+			"views":       types.IntegerNumberProperty{},
+			"comments":    types.IntegerNumberProperty{},
+			"likes":       types.IntegerNumberProperty{},
+			"author_uuid": types.KeywordProperty{},
 		},
 	}
 }
+
+// ArticleMappingUpdate 在已有的文章索引中补充新增字段的映射
+func ArticleMappingUpdate() map[string]types.Property {
+	return map[string]types.Property{
+		"author_uuid": types.KeywordProperty{},
+	}
+}
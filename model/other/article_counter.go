@@ -0,0 +1,9 @@
+package other
+
+// CounterDiscrepancy 文章计数在 ES 与 MySQL 之间的一处差异
+type CounterDiscrepancy struct {
+	ArticleID string `json:"article_id"` // 文章 ID
+	Field     string `json:"field"`      // 计数字段（likes/comments）
+	Old       int    `json:"old"`        // ES 中原来的值
+	New       int    `json:"new"`        // 根据 MySQL 重新计算出的值
+}
@@ -0,0 +1,26 @@
+package other
+
+// OIDCDiscovery OpenID Connect 发现文档中登录所需的字段
+type OIDCDiscovery struct {
+	Issuer                string `json:"issuer"`                 // 签发者
+	AuthorizationEndpoint string `json:"authorization_endpoint"` // 授权地址
+	TokenEndpoint         string `json:"token_endpoint"`         // 获取令牌的地址
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`      // 获取用户信息的地址
+}
+
+// OAuthState 发起第三方登录时保存在 Redis 中的 state 信息
+type OAuthState struct {
+	Provider string `json:"provider"` // 登录提供方名称
+	Purpose  string `json:"purpose"`  // 用途：login 表示登录，link 表示关联到已登录的账号
+	UserID   uint   `json:"user_id"`  // 关联账号时发起请求的用户 ID
+}
+
+// OAuthUserInfo 从登录提供方获取到的用户信息
+type OAuthUserInfo struct {
+	Provider      string // 登录提供方名称
+	Subject       string // 用户在提供方的唯一标识
+	Email         string // 邮箱
+	EmailVerified bool   // 提供方是否确认邮箱已验证，未验证的邮箱不会保存到账号
+	Name          string // 昵称
+	Avatar        string // 头像
+}
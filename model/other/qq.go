@@ -0,0 +1,32 @@
+package other
+
+// QQError QQ 互联接口返回的错误信息
+type QQError struct {
+	Error            int    `json:"error"`             // 错误码，0 表示成功
+	ErrorDescription string `json:"error_description"` // 错误描述
+}
+
+// AccessTokenResponse 通过 Authorization Code 获取 Access Token 的响应结果
+type AccessTokenResponse struct {
+	QQError
+	AccessToken  string `json:"access_token"`  // 授权令牌
+	ExpiresIn    string `json:"expires_in"`    // 该 Access Token 的有效期，单位为秒
+	RefreshToken string `json:"refresh_token"` // 在授权自动续期步骤中，获取新的 Access Token 时需要提供的参数
+}
+
+// OpenIDResponse 通过 Access Token 获取 OpenID 的响应结果
+type OpenIDResponse struct {
+	QQError
+	ClientID string `json:"client_id"` // 应用 ID
+	OpenID   string `json:"openid"`    // 用户的唯一标识
+}
+
+// UserInfoResponse 获取 QQ 用户信息的响应结果
+type UserInfoResponse struct {
+	Ret          int    `json:"ret"`            // 返回码，0 表示成功
+	Msg          string `json:"msg"`            // 如果 ret < 0，会有相应的错误信息提示
+	Nickname     string `json:"nickname"`       // 用户在 QQ 空间的昵称
+	FigureURL    string `json:"figureurl"`      // 大小为 30×30 像素的 QQ 空间头像 URL
+	FigureURLQQ1 string `json:"figureurl_qq_1"` // 大小为 40×40 像素的 QQ 头像 URL
+	FigureURLQQ2 string `json:"figureurl_qq_2"` // 大小为 100×100 像素的 QQ 头像 URL，不一定都有
+}
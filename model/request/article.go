@@ -16,20 +16,53 @@ type ArticleSearch struct {
 type ArticleLike struct {
 	UserID    uint   `json:"-"`
 	ArticleID string `json:"article_id" form:"article_id" binding:"required"`
+	FolderID  uint   `json:"folder_id" form:"folder_id"` // 收藏时放入的收藏夹，0 表示未分类
 }
 
 type ArticleLikesList struct {
-	UserID uint `json:"-"`
+	UserID   uint  `json:"-"`
+	FolderID *uint `json:"folder_id" form:"folder_id"` // 按收藏夹筛选，0 表示未分类，不传表示全部
 	PageInfo
 }
 
+type BookmarkFolderCreate struct {
+	UserID      uint   `json:"-"`
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description" binding:"max=200"`
+}
+
+type BookmarkFolderUpdate struct {
+	UserID      uint   `json:"-"`
+	ID          uint   `json:"id" binding:"required"`
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description" binding:"max=200"`
+}
+
+type BookmarkFolderDelete struct {
+	UserID uint `json:"-"`
+	ID     uint `json:"id" binding:"required"`
+}
+
+type BookmarkMove struct {
+	UserID     uint     `json:"-"`
+	ArticleIDs []string `json:"article_ids" binding:"required,min=1"`
+	FolderID   uint     `json:"folder_id"` // 目标收藏夹，0 表示未分类
+}
+
+type BookmarkNote struct {
+	UserID    uint   `json:"-"`
+	ArticleID string `json:"article_id" binding:"required"`
+	Note      string `json:"note" binding:"max=500"`
+}
+
 type ArticleCreate struct {
-	Cover    string   `json:"cover" binding:"required"`
-	Title    string   `json:"title" binding:"required"`
-	Category string   `json:"category" binding:"required"`
-	Tags     []string `json:"tags" binding:"required"`
-	Abstract string   `json:"abstract" binding:"required"`
-	Content  string   `json:"content" binding:"required"`
+	AuthorUUID string   `json:"-"`
+	Cover      string   `json:"cover" binding:"required"`
+	Title      string   `json:"title" binding:"required"`
+	Category   string   `json:"category" binding:"required"`
+	Tags       []string `json:"tags" binding:"required"`
+	Abstract   string   `json:"abstract" binding:"required"`
+	Content    string   `json:"content" binding:"required"`
 }
 
 type ArticleDelete struct {
@@ -52,3 +85,25 @@ type ArticleList struct {
 	Abstract *string `json:"abstract" form:"abstract"`
 	PageInfo
 }
+
+type ReadingBeacon struct {
+	UserID    uint    `json:"-"`
+	ArticleID string  `json:"article_id" binding:"required,max=64"`
+	Progress  float64 `json:"progress" binding:"min=0,max=1"` // 滚动比例
+	Position  string  `json:"position" binding:"max=200"`     // 当前所在的标题锚点
+}
+
+type ReadingHistoryList struct {
+	UserID uint `json:"-"`
+	PageInfo
+}
+
+type ReadingHistoryClear struct {
+	UserID     uint     `json:"-"`
+	ArticleIDs []string `json:"article_ids"` // 要删除的阅读记录，为空时清空全部
+}
+
+type ReadingResume struct {
+	UserID    uint   `json:"-"`
+	ArticleID string `json:"article_id" form:"article_id" binding:"required"`
+}
@@ -2,6 +2,7 @@ package request
 
 type SendEmailVerificationCode struct {
 	Email     string `json:"email" binding:"required,email"`
+	Purpose   string `json:"purpose" binding:"omitempty,oneof=register reset change-email"` // 验证码用途，为空时根据邮箱是否已注册判断
 	Captcha   string `json:"captcha" binding:"required,len=6"`
 	CaptchaID string `json:"captcha_id" binding:"required"`
 }
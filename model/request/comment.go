@@ -13,6 +13,25 @@ type CommentCreate struct {
 	Content   string    `json:"content" binding:"required,max=320"`
 }
 
+type CommentGuestCreate struct {
+	ArticleID string `json:"article_id" binding:"required"`
+	PID       *uint  `json:"p_id"`
+	Nickname  string `json:"nickname" binding:"required,max=20"`
+	Email     string `json:"email" binding:"required,email"`
+	Content   string `json:"content" binding:"required,max=320"`
+	Captcha   string `json:"captcha" binding:"required,len=6"`
+	CaptchaID string `json:"captcha_id" binding:"required"`
+}
+
+type CommentGuestVerify struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+type CommentReview struct {
+	IDs     []uint `json:"ids" binding:"required"`
+	Approve bool   `json:"approve"`
+}
+
 type CommentDelete struct {
 	IDs []uint `json:"ids"`
 }
@@ -21,5 +40,6 @@ type CommentList struct {
 	ArticleID *string `json:"article_id" form:"article_id"`
 	UserUUID  *string `json:"user_uuid" form:"user_uuid"`
 	Content   *string `json:"content" form:"content"`
+	Status    *string `json:"status" form:"status"`
 	PageInfo
 }
@@ -0,0 +1,25 @@
+package request
+
+import "server/model/appTypes"
+
+type Follow struct {
+	UserID     uint                `json:"-"`
+	TargetType appTypes.FollowType `json:"target_type" form:"target_type" binding:"required"`
+	Target     string              `json:"target" form:"target" binding:"required"`
+}
+
+type FollowList struct {
+	UserID     uint                `json:"-"`
+	TargetType appTypes.FollowType `json:"target_type" form:"target_type"`
+	PageInfo
+}
+
+type FollowUsers struct {
+	UUID string `json:"uuid" form:"uuid" binding:"required"`
+	PageInfo
+}
+
+type ArticleFeed struct {
+	UserID uint `json:"-"`
+	PageInfo
+}
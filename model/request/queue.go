@@ -0,0 +1,11 @@
+package request
+
+type DeadLetterList struct {
+	Queue string `json:"queue" form:"queue"` // 原队列名称，默认为 ES 更新队列
+	Limit int    `json:"limit" form:"limit" binding:"min=0,max=100"`
+}
+
+type DeadLetterReplay struct {
+	Queue string `json:"queue"` // 原队列名称，默认为 ES 更新队列
+	Limit int    `json:"limit" binding:"required,min=1,max=1000"`
+}
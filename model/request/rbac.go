@@ -0,0 +1,28 @@
+package request
+
+type RoleCreate struct {
+	Name        string   `json:"name" binding:"required,max=64"`
+	DisplayName string   `json:"display_name" binding:"required,max=50"`
+	Description string   `json:"description" binding:"max=200"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleUpdate struct {
+	ID          uint     `json:"id" binding:"required"`
+	DisplayName string   `json:"display_name" binding:"required,max=50"`
+	Description string   `json:"description" binding:"max=200"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleDelete struct {
+	ID uint `json:"id" binding:"required"`
+}
+
+type UserRoles struct {
+	UserID uint `json:"user_id" form:"user_id" binding:"required"`
+}
+
+type UserRolesUpdate struct {
+	UserID  uint   `json:"user_id" binding:"required"`
+	RoleIDs []uint `json:"role_ids"`
+}
@@ -2,20 +2,46 @@ package request
 
 type Register struct {
 	Username         string `json:"username" binding:"required,max=20"`
-	Password         string `json:"password" binding:"required,min=8,max=16"`
+	Password         string `json:"password" binding:"required,min=8,max=64"`
 	Email            string `json:"email" binding:"required,email"`
 	VerificationCode string `json:"verification_code" binding:"required,len=6"`
 }
 type Login struct {
 	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=8,max=16"`
+	Password  string `json:"password" binding:"required,min=8,max=64"`
 	Captcha   string `json:"captcha" binding:"required,len=6"`
 	CaptchaID string `json:"captcha_id" binding:"required"`
 }
+type QQLogin struct {
+	Code  string `json:"code" form:"code" binding:"required"`
+	State string `json:"state" form:"state" binding:"required"`
+}
+
+type OAuthLogin struct {
+	Provider string `json:"provider" form:"provider" binding:"required"`
+	Code     string `json:"code" form:"code" binding:"required"`
+	State    string `json:"state" form:"state" binding:"required"`
+}
+
+type OAuthProvider struct {
+	Provider string `json:"provider" form:"provider" binding:"required"`
+}
+
 type ForgotPassword struct {
 	Email            string `json:"email" binding:"required,email"`
 	VerificationCode string `json:"verification_code" binding:"required,len=6"`
-	NewPassword      string `json:"new_password" binding:"required,min=8,max=16"`
+	NewPassword      string `json:"new_password" binding:"required,min=8,max=64"`
+}
+
+type PasswordResetRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	Captcha   string `json:"captcha" binding:"required,len=6"`
+	CaptchaID string `json:"captcha_id" binding:"required"`
+}
+
+type PasswordReset struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=64"`
 }
 
 type UserCard struct {
@@ -23,8 +49,8 @@ type UserCard struct {
 }
 type UserResetPassword struct {
 	UserID      uint   `json:"-"`
-	Password    string `json:"password" binding:"required,min=8,max=16"`
-	NewPassword string `json:"new_password" binding:"required,min=8,max=16"`
+	Password    string `json:"password" binding:"required,min=8,max=64"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=64"`
 }
 
 type UserChangeInfo struct {
@@ -51,3 +77,51 @@ type UserLoginList struct {
 	UUID *string `json:"uuid" form:"uuid"`
 	PageInfo
 }
+
+type TwoFactorConfirm struct {
+	Code string `json:"code" binding:"required,len=6"`
+}
+
+type TwoFactorDisable struct {
+	Password string `json:"password"` // 设置了密码的账户需要提供密码
+	Code     string `json:"code"`     // 没有密码的账户（QQ、第三方登录）需要提供动态码或恢复码
+}
+
+type TwoFactorVerify struct {
+	PendingToken string `json:"pending_token" binding:"required"`
+	Code         string `json:"code" binding:"required"`
+}
+
+type SessionRevoke struct {
+	ID uint `json:"id" binding:"required"`
+}
+
+type UserChangeEmail struct {
+	UserID           uint   `json:"-"`
+	Email            string `json:"email" binding:"required,email"`
+	VerificationCode string `json:"verification_code" binding:"required,len=6"`
+	Password         string `json:"password"`
+}
+
+type UserDeletionRequest struct {
+	Password string `json:"password"`
+}
+
+type UserExportDownload struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+type PersonalAccessTokenCreate struct {
+	UserID    uint     `json:"-"`
+	Name      string   `json:"name" binding:"required,max=50"`
+	Scopes    []string `json:"scopes" binding:"required,min=1"`
+	ExpiresIn int      `json:"expires_in" binding:"min=0,max=3650"` // 有效天数，0 表示永不过期
+}
+
+type PersonalAccessTokenRevoke struct {
+	ID uint `json:"id" binding:"required"`
+}
+
+type LoginAlertReject struct {
+	Token string `json:"token" binding:"required"`
+}
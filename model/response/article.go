@@ -0,0 +1,29 @@
+package response
+
+import (
+	"server/model/database"
+	"server/model/elasticsearch"
+	"time"
+)
+
+type Bookmark struct {
+	Id_       string                `json:"_id"`
+	Source_   elasticsearch.Article `json:"_source"`
+	FolderID  *uint                 `json:"folder_id"`
+	Note      string                `json:"note"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+type BookmarkFolder struct {
+	database.BookmarkFolder
+	Count int64 `json:"count"` // 收藏夹中的文章数量
+}
+
+type ReadingHistory struct {
+	ArticleID string    `json:"article_id"`
+	Title     string    `json:"title"` // 文章已删除时为空
+	Cover     string    `json:"cover"`
+	Progress  float64   `json:"progress"`
+	Position  string    `json:"position"`
+	ReadAt    time.Time `json:"read_at"`
+}
@@ -4,3 +4,8 @@ type Captcha struct {
 	CaptchaId string `json:"captcha_id"`
 	PicPath   string `json:"pic_path"`
 }
+
+type OAuthProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
@@ -62,3 +62,11 @@ func Forbidden(message string, c *gin.Context) {
 		Msg:  message,
 	})
 }
+
+func TooManyRequests(message string, c *gin.Context) {
+	c.JSON(http.StatusTooManyRequests, Response{
+		Code: Error,
+		Data: nil,
+		Msg:  message,
+	})
+}
@@ -12,14 +12,40 @@ type Login struct {
 }
 
 type UserCard struct {
-	UUID      uuid.UUID `json:"uuid"`
-	Username  string    `json:"username"`
-	Avatar    string    `json:"avatar"`
-	Address   string    `json:"address"`
-	Signature string    `json:"signature"`
+	UUID           uuid.UUID `json:"uuid"`
+	Username       string    `json:"username"`
+	Avatar         string    `json:"avatar"`
+	Address        string    `json:"address"`
+	Signature      string    `json:"signature"`
+	FollowerCount  int64     `json:"follower_count"`  // 粉丝数
+	FollowingCount int64     `json:"following_count"` // 关注的用户数
 }
 type UserChart struct {
 	DateList     []string `json:"date_list"`
 	LoginData    []int    `json:"login_data"`
 	RegisterData []int    `json:"register_data"`
 }
+
+type TwoFactorEnroll struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type TwoFactorPending struct {
+	PendingToken string `json:"pending_token"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
+type Session struct {
+	database.Session
+	Current bool `json:"current"`
+}
+
+type PersonalAccessTokenCreate struct {
+	Token               string                       `json:"token"`
+	PersonalAccessToken database.PersonalAccessToken `json:"personal_access_token"`
+}
+
+type LoginAlertReject struct {
+	ResetToken string `json:"reset_token"`
+}
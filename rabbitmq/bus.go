@@ -0,0 +1,94 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"server/eventbus"
+	"server/global"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Bus 基于 RabbitMQ 的事件总线，主题对应同名的持久化队列，订阅者在监督下运行，断开后自动重新连接
+type Bus struct {
+	closeOnce sync.Once
+}
+
+// message RabbitMQ 投递的一条事件
+type message struct {
+	delivery amqp.Delivery
+	queue    string
+	retry    bool
+}
+
+// NewBus 创建基于 RabbitMQ 的事件总线，需要先初始化 global.RmqConn 和默认的发布者
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Publish 通过默认的发布者发布事件
+func (b *Bus) Publish(topic string, payload []byte) error {
+	return PublishMessage(global.RmqConn, topic, payload)
+}
+
+// Subscribe 在监督下启动消费者，开启重试时声明重试队列和死信队列
+func (b *Bus) Subscribe(topic string, opts eventbus.SubscribeOptions, handler eventbus.Handler) {
+	StartConsumer(opts.Name, topic, func(ctx context.Context, conn *amqp.Connection) error {
+		ch, err := conn.Channel()
+		if err != nil {
+			return fmt.Errorf("failed to open channel: %v", err)
+		}
+		// 关闭 Channel 时预取但还没有处理的消息会回到队列
+		defer ch.Close()
+		if _, err = declareQueue(ch, topic); err != nil {
+			return fmt.Errorf("queue declare failed: %v", err)
+		}
+		if opts.Retry {
+			if err = declareRetryTopology(ch, topic); err != nil {
+				return fmt.Errorf("retry queue declare failed: %v", err)
+			}
+		}
+
+		// 消息在处理完成后才确认，预取数量至少要能装下一批
+		msgs, err := ConsumeMessages(ch, topic, max(opts.BatchSize, 1))
+		if err != nil {
+			return fmt.Errorf("failed to consume messages: %v", err)
+		}
+		return eventbus.RunBatches(ctx, msgs, opts.BatchSize, opts.FlushInterval, func(batch []amqp.Delivery) {
+			list := make([]eventbus.Message, 0, len(batch))
+			for _, d := range batch {
+				list = append(list, &message{delivery: d, queue: topic, retry: opts.Retry})
+			}
+			handler(list)
+		})
+	})
+}
+
+// Close 停止消费者并等待它们处理完已经收到的消息，然后关闭发布者和连接
+func (b *Bus) Close() {
+	b.closeOnce.Do(func() {
+		StopConsumers()
+		ClosePublisher()
+		if global.RmqConn != nil {
+			global.RmqConn.Close()
+		}
+	})
+}
+
+func (m *message) Body() []byte {
+	return m.delivery.Body
+}
+
+func (m *message) Ack() {
+	m.delivery.Ack(false)
+}
+
+// Fail 开启重试时转移到重试队列或死信队列，否则直接丢弃
+func (m *message) Fail(reason string, retryable bool) {
+	if m.retry {
+		retryOrDeadLetter(m.delivery, m.queue, reason, retryable)
+		return
+	}
+	m.delivery.Nack(false, false)
+}
@@ -6,17 +6,13 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-func ConsumeMessages(conn *amqp.Connection, queueName string) (<-chan amqp.Delivery, error) {
-	ch, err := conn.Channel()
-	if err != nil {
-		return nil, fmt.Errorf("failed to open channel: %v", err)
-	}
-
+// ConsumeMessages 在 Channel 上消费队列，prefetch 为未确认消息的最大数量，Channel 关闭后返回的通道也会关闭
+func ConsumeMessages(ch *amqp.Channel, queueName string, prefetch int) (<-chan amqp.Delivery, error) {
 	// 设置 QoS 控制并发
-	err = ch.Qos(
-		1,     // prefetchCount
-		0,     // prefetchSize
-		false, // global
+	err := ch.Qos(
+		prefetch, // prefetchCount
+		0,        // prefetchSize
+		false,    // global
 	)
 	if err != nil {
 		return nil, fmt.Errorf("qos设置失败: %v", err)
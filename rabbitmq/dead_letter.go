@@ -0,0 +1,100 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeadLetter 死信队列中的一条消息
+type DeadLetter struct {
+	Body           string    `json:"body"`             // 消息内容
+	Queue          string    `json:"queue"`            // 消息原本所在的队列
+	Attempts       int       `json:"attempts"`         // 已经处理失败的次数
+	Error          string    `json:"error"`            // 最后一次处理失败的原因
+	DeadLetteredAt time.Time `json:"dead_lettered_at"` // 进入死信队列的时间
+}
+
+// DeadLetters 查看队列对应的死信队列中最早的 limit 条消息，并返回死信队列中的消息总数，查看不会移除消息
+func DeadLetters(queue string, limit int) ([]DeadLetter, int, error) {
+	conn, err := Connection()
+	if err != nil {
+		return nil, 0, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open channel: %v", err)
+	}
+	// 关闭 Channel 时未确认的消息会回到死信队列
+	defer ch.Close()
+
+	q, err := declareQueue(ch, DeadLetterQueue(queue))
+	if err != nil {
+		return nil, 0, err
+	}
+	list := make([]DeadLetter, 0, min(limit, q.Messages))
+	for len(list) < limit {
+		msg, ok, err := ch.Get(q.Name, false)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ok {
+			break
+		}
+		letter := DeadLetter{
+			Body:     string(msg.Body),
+			Queue:    queue,
+			Attempts: retryCount(msg.Headers),
+		}
+		if v, ok := msg.Headers[lastErrorHeader].(string); ok {
+			letter.Error = v
+		}
+		if v, ok := msg.Headers[originalQueueHeader].(string); ok {
+			letter.Queue = v
+		}
+		if v, ok := msg.Headers[deadLetteredAtHeader].(string); ok {
+			letter.DeadLetteredAt, _ = time.Parse(time.RFC3339, v)
+		}
+		list = append(list, letter)
+	}
+	return list, q.Messages, nil
+}
+
+// ReplayDeadLetters 把死信队列中最早的 limit 条消息重新发布到原队列，重试次数从零开始，返回重新发布的数量
+func ReplayDeadLetters(queue string, limit int) (int, error) {
+	conn, err := Connection()
+	if err != nil {
+		return 0, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open channel: %v", err)
+	}
+	defer ch.Close()
+
+	q, err := declareQueue(ch, DeadLetterQueue(queue))
+	if err != nil {
+		return 0, err
+	}
+	var replayed int
+	for replayed < limit {
+		msg, ok, err := ch.Get(q.Name, false)
+		if err != nil {
+			return replayed, err
+		}
+		if !ok {
+			break
+		}
+		target := queue
+		if v, ok := msg.Headers[originalQueueHeader].(string); ok && v != "" {
+			target = v
+		}
+		if err := publishConfirmed(target, msg.Body, nil); err != nil {
+			return replayed, err
+		}
+		if err := msg.Ack(false); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
@@ -8,7 +8,12 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// PublishMessage 发布消息到指定队列。初始化了发布者时通过它复用 Channel 并等待确认，conn 只在未初始化发布者时使用
 func PublishMessage(conn *amqp.Connection, queueName string, message []byte) error {
+	if defaultPublisher != nil {
+		return defaultPublisher.Publish(queueName, message)
+	}
+
 	ch, err := conn.Channel()
 	if err != nil {
 		return fmt.Errorf("failed to open channel: %v", err)
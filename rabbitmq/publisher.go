@@ -0,0 +1,356 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server/global"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrPublisherClosed   = errors.New("rabbitmq publisher is closed")
+	ErrPublishBufferFull = errors.New("rabbitmq publish buffer is full, the message was dropped")
+	ErrPublishNacked     = errors.New("the message was rejected by the broker")
+	errNotConnected      = errors.New("rabbitmq publisher is not connected")
+)
+
+// maxRetryInterval 重新发布和重新连接的最大间隔
+const maxRetryInterval = 30 * time.Second
+
+// defaultPublisher 由 InitPublisher 初始化，PublishMessage 通过它发布消息
+var defaultPublisher *Publisher
+
+// Publisher 复用 Channel 发布消息并等待 Broker 确认，发布失败的消息先缓存在本地，由后台协程重试，
+// 连接断开后自动重新连接。发布者使用单独的连接，避免 Broker 对发布方限流时影响消费者
+type Publisher struct {
+	url            string
+	confirmTimeout time.Duration
+	retryInterval  time.Duration
+
+	mu       sync.RWMutex
+	conn     *amqp.Connection
+	declared map[string]bool // 当前连接上已经声明过的队列
+
+	channels chan *amqp.Channel  // 空闲的 Channel，均已开启发布确认
+	buffer   chan pendingMessage // 等待重试的消息
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// pendingMessage 等待重试的消息
+type pendingMessage struct {
+	queue   string
+	body    []byte
+	headers amqp.Table
+}
+
+// InitPublisher 根据配置创建默认的发布者，之后 PublishMessage 都通过它发布消息
+func InitPublisher() {
+	cfg := global.Config.RabbitMQ
+	publisher, err := NewPublisher(cfg.Dial, cfg.Publisher.ChannelPoolSize, cfg.Publisher.BufferSize, cfg.Publisher.ConfirmTimeout, cfg.Publisher.RetryInterval)
+	if err != nil {
+		global.Log.Error("Failed to create RabbitMQ publisher:", zap.Error(err))
+		return
+	}
+	defaultPublisher = publisher
+}
+
+// ClosePublisher 关闭默认的发布者，关闭前会尽量发布缓存中的消息
+func ClosePublisher() {
+	if defaultPublisher != nil {
+		defaultPublisher.Close()
+	}
+}
+
+// NewPublisher 创建发布者，首次连接失败时不会返回错误，而是在后台继续重试，期间的消息进入本地缓存
+func NewPublisher(url string, poolSize, bufferSize int, confirmTimeout, retryInterval string) (*Publisher, error) {
+	p := &Publisher{
+		url:            url,
+		confirmTimeout: 5 * time.Second,
+		retryInterval:  time.Second,
+		declared:       make(map[string]bool),
+		channels:       make(chan *amqp.Channel, max(poolSize, 1)),
+		buffer:         make(chan pendingMessage, max(bufferSize, 0)),
+		done:           make(chan struct{}),
+	}
+	if confirmTimeout != "" {
+		d, err := time.ParseDuration(confirmTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid confirm timeout: %v", err)
+		}
+		p.confirmTimeout = d
+	}
+	if retryInterval != "" {
+		d, err := time.ParseDuration(retryInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry interval: %v", err)
+		}
+		p.retryInterval = d
+	}
+
+	if conn, err := amqp.Dial(url); err != nil {
+		global.Log.Error("Failed to connect RabbitMQ publisher, retrying in background:", zap.Error(err))
+	} else {
+		p.conn = conn
+	}
+
+	p.wg.Add(2)
+	go p.watch()
+	go p.retry()
+	return p, nil
+}
+
+// Publish 发布消息并等待 Broker 确认，失败时放入本地缓存稍后重试，缓存已满时返回错误
+func (p *Publisher) Publish(queue string, body []byte) error {
+	select {
+	case <-p.done:
+		return ErrPublisherClosed
+	default:
+	}
+
+	err := p.publish(queue, body, nil)
+	if err == nil {
+		return nil
+	}
+	select {
+	case p.buffer <- pendingMessage{queue: queue, body: body}:
+		global.Log.Warn("Failed to publish message, buffered for retry", zap.String("queue", queue), zap.Error(err))
+		return nil
+	default:
+		return fmt.Errorf("%w: %v", ErrPublishBufferFull, err)
+	}
+}
+
+// PublishConfirmed 发布消息并等待 Broker 确认，失败时直接返回错误，由调用方决定如何处理原消息
+func (p *Publisher) PublishConfirmed(queue string, body []byte, headers amqp.Table) error {
+	select {
+	case <-p.done:
+		return ErrPublisherClosed
+	default:
+	}
+	return p.publish(queue, body, headers)
+}
+
+// Buffered 返回等待重试的消息数量
+func (p *Publisher) Buffered() int {
+	return len(p.buffer)
+}
+
+// Close 停止重连和重试，尽量发布缓存中的消息后关闭连接
+func (p *Publisher) Close() {
+	p.closeOnce.Do(func() { close(p.done) })
+	p.wg.Wait()
+
+	var lost int
+	for n := len(p.buffer); n > 0; n-- {
+		msg := <-p.buffer
+		if err := p.publish(msg.queue, msg.body, msg.headers); err != nil {
+			lost++
+		}
+	}
+	if lost > 0 {
+		global.Log.Error("RabbitMQ publisher closed with unpublished messages", zap.Int("count", lost))
+	}
+
+	for n := len(p.channels); n > 0; n-- {
+		ch := <-p.channels
+		ch.Close()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+}
+
+// publish 通过空闲的 Channel 发布一条消息并等待确认
+func (p *Publisher) publish(queue string, body []byte, headers amqp.Table) error {
+	ch, err := p.channel()
+	if err != nil {
+		return err
+	}
+	reusable := false
+	defer func() { p.release(ch, reusable) }()
+
+	if err := p.declare(ch, queue); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.confirmTimeout)
+	defer cancel()
+	confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx,
+		"",    // exchange
+		queue, // routing key
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			Headers:      headers,
+			DeliveryMode: amqp.Persistent,
+			ContentType:  "application/json",
+			Body:         body,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %v", err)
+	}
+	// 等待确认超时的 Channel 状态不确定，不再复用
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for publisher confirm: %v", err)
+	}
+	reusable = true
+	if !acked {
+		return ErrPublishNacked
+	}
+	return nil
+}
+
+// channel 取出一个空闲的 Channel，没有时在当前连接上新建
+func (p *Publisher) channel() (*amqp.Channel, error) {
+	for {
+		select {
+		case ch := <-p.channels:
+			// 连接断开后旧的 Channel 都已关闭，直接丢弃
+			if !ch.IsClosed() {
+				return ch, nil
+			}
+		default:
+			p.mu.RLock()
+			conn := p.conn
+			p.mu.RUnlock()
+			if conn == nil || conn.IsClosed() {
+				return nil, errNotConnected
+			}
+			ch, err := conn.Channel()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open channel: %v", err)
+			}
+			if err := ch.Confirm(false); err != nil {
+				ch.Close()
+				return nil, fmt.Errorf("failed to enable publisher confirms: %v", err)
+			}
+			return ch, nil
+		}
+	}
+}
+
+// release 归还 Channel，池已满或 Channel 不可复用时关闭它
+func (p *Publisher) release(ch *amqp.Channel, reusable bool) {
+	if reusable && !ch.IsClosed() {
+		select {
+		case p.channels <- ch:
+			return
+		default:
+		}
+	}
+	ch.Close()
+}
+
+// declare 声明队列，每个连接上同一个队列只声明一次
+func (p *Publisher) declare(ch *amqp.Channel, queue string) error {
+	p.mu.RLock()
+	declared := p.declared[queue]
+	p.mu.RUnlock()
+	if declared {
+		return nil
+	}
+
+	if _, err := declareQueue(ch, queue); err != nil {
+		return fmt.Errorf("queue declare failed: %v", err)
+	}
+	p.mu.Lock()
+	p.declared[queue] = true
+	p.mu.Unlock()
+	return nil
+}
+
+// watch 监听连接断开，断开后按退避间隔重新连接
+func (p *Publisher) watch() {
+	defer p.wg.Done()
+	for {
+		p.mu.RLock()
+		conn := p.conn
+		p.mu.RUnlock()
+
+		if conn != nil {
+			closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+			select {
+			case <-p.done:
+				return
+			case err := <-closed:
+				global.Log.Warn("RabbitMQ publisher connection closed:", zap.Any("reason", err))
+			}
+		}
+
+		if !p.reconnect() {
+			return
+		}
+	}
+}
+
+// reconnect 重新连接直到成功，发布者关闭时返回 false
+func (p *Publisher) reconnect() bool {
+	interval := p.retryInterval
+	for {
+		select {
+		case <-p.done:
+			return false
+		case <-time.After(interval):
+		}
+
+		conn, err := amqp.Dial(p.url)
+		if err != nil {
+			global.Log.Error("Failed to reconnect RabbitMQ publisher:", zap.Error(err))
+			interval = min(interval*2, maxRetryInterval)
+			continue
+		}
+		p.mu.Lock()
+		p.conn = conn
+		p.declared = make(map[string]bool)
+		p.mu.Unlock()
+		global.Log.Info("RabbitMQ publisher reconnected")
+		return true
+	}
+}
+
+// retry 按顺序重新发布缓存中的消息，失败时按退避间隔重试
+func (p *Publisher) retry() {
+	defer p.wg.Done()
+	for {
+		var msg pendingMessage
+		select {
+		case <-p.done:
+			return
+		case msg = <-p.buffer:
+		}
+
+		interval := p.retryInterval
+		for {
+			err := p.publish(msg.queue, msg.body, msg.headers)
+			if err == nil {
+				break
+			}
+			global.Log.Warn("Failed to republish buffered message", zap.String("queue", msg.queue), zap.Error(err))
+			select {
+			case <-p.done:
+				// 放回缓存，由 Close 做最后一次尝试
+				select {
+				case p.buffer <- msg:
+				default:
+					global.Log.Error("Dropped buffered message on close", zap.String("queue", msg.queue))
+				}
+				return
+			case <-time.After(interval):
+			}
+			interval = min(interval*2, maxRetryInterval)
+		}
+	}
+}
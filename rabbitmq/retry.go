@@ -0,0 +1,118 @@
+package rabbitmq
+
+import (
+	"errors"
+	"server/global"
+	"server/utils"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// 处理失败的消息带上的消息头
+const (
+	retryCountHeader     = "x-retry-count"      // 已经重试的次数
+	lastErrorHeader      = "x-last-error"       // 最后一次处理失败的原因
+	originalQueueHeader  = "x-original-queue"   // 消息原本所在的队列
+	deadLetteredAtHeader = "x-dead-lettered-at" // 进入死信队列的时间
+)
+
+// queueArgs 需要特殊参数的队列，声明队列时使用，保证发布者和消费者声明的参数一致
+var (
+	queueArgsMu sync.RWMutex
+	queueArgs   = map[string]amqp.Table{}
+)
+
+// RetryQueue 队列在指定重试间隔下对应的重试队列，消息在其中过期后回到原队列
+func RetryQueue(queue string, delay time.Duration) string {
+	return queue + ".retry." + delay.String()
+}
+
+// DeadLetterQueue 队列对应的死信队列，多次重试仍然失败或无法处理的消息会进入这里
+func DeadLetterQueue(queue string) string {
+	return queue + ".dead_letter"
+}
+
+// declareQueue 声明持久化队列，使用登记过的队列参数
+func declareQueue(ch *amqp.Channel, queue string) (amqp.Queue, error) {
+	queueArgsMu.RLock()
+	args := queueArgs[queue]
+	queueArgsMu.RUnlock()
+	return ch.QueueDeclare(
+		queue, // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		args,  // arguments
+	)
+}
+
+// declareRetryTopology 声明队列的重试队列和死信队列，重试队列中的消息过期后通过默认交换机回到原队列
+func declareRetryTopology(ch *amqp.Channel, queue string) error {
+	for _, delay := range utils.ParseRetryDelays(global.Config.EventBus.RetryDelays) {
+		name := RetryQueue(queue, delay)
+		queueArgsMu.Lock()
+		queueArgs[name] = amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": queue,
+		}
+		queueArgsMu.Unlock()
+		if _, err := declareQueue(ch, name); err != nil {
+			return err
+		}
+	}
+	_, err := declareQueue(ch, DeadLetterQueue(queue))
+	return err
+}
+
+// retryOrDeadLetter 把处理失败的消息发布到下一级重试队列，不可重试或重试次数用完时发布到死信队列，发布成功后确认原消息
+func retryOrDeadLetter(msg amqp.Delivery, queue, reason string, retryable bool) {
+	attempts := retryCount(msg.Headers)
+	delays := utils.ParseRetryDelays(global.Config.EventBus.RetryDelays)
+	headers := amqp.Table{
+		retryCountHeader:    int64(attempts + 1),
+		lastErrorHeader:     reason,
+		originalQueueHeader: queue,
+	}
+
+	target := DeadLetterQueue(queue)
+	if retryable && attempts < len(delays) {
+		target = RetryQueue(queue, delays[attempts])
+	} else {
+		headers[deadLetteredAtHeader] = time.Now().Format(time.RFC3339)
+		global.Log.Warn("Message moved to dead-letter queue", zap.String("queue", queue), zap.Int("attempts", attempts), zap.String("reason", reason))
+	}
+
+	if err := publishConfirmed(target, msg.Body, headers); err != nil {
+		// 无法转移时放回原队列，稍后重新处理
+		global.Log.Error("Failed to move message for retry", zap.String("queue", target), zap.Error(err))
+		msg.Nack(false, true)
+		return
+	}
+	msg.Ack(false)
+}
+
+// publishConfirmed 通过默认的发布者发布消息并等待确认，失败时不会进入本地缓存
+func publishConfirmed(queue string, body []byte, headers amqp.Table) error {
+	if defaultPublisher == nil {
+		return errors.New("rabbitmq publisher is not initialized")
+	}
+	return defaultPublisher.PublishConfirmed(queue, body, headers)
+}
+
+// retryCount 从消息头中读取已经重试的次数
+func retryCount(headers amqp.Table) int {
+	switch v := headers[retryCountHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
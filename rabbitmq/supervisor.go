@@ -0,0 +1,195 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"server/global"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// ConsumerState 消费者的运行状态
+type ConsumerState string
+
+const (
+	ConsumerStarting     ConsumerState = "starting"     // 正在连接
+	ConsumerRunning      ConsumerState = "running"      // 正在消费
+	ConsumerReconnecting ConsumerState = "reconnecting" // 连接或 Channel 断开，等待重新连接
+	ConsumerStopped      ConsumerState = "stopped"      // 已停止
+)
+
+// ConsumerStatus 消费者的状态
+type ConsumerStatus struct {
+	Name      string        `json:"name"`       // 消费者名称
+	Queue     string        `json:"queue"`      // 消费的队列
+	State     ConsumerState `json:"state"`      // 当前状态
+	Since     time.Time     `json:"since"`      // 进入当前状态的时间
+	Restarts  int           `json:"restarts"`   // 重新启动的次数
+	LastError string        `json:"last_error"` // 最后一次停止的原因
+}
+
+// ConsumerFunc 在给定连接上消费队列，直到连接或 Channel 断开时返回错误；
+// ctx 取消时需要处理完已经收到的消息再返回，未确认的消息会在 Channel 关闭后回到队列
+type ConsumerFunc func(ctx context.Context, conn *amqp.Connection) error
+
+// consumer 由监督者运行的消费者
+type consumer struct {
+	run    ConsumerFunc
+	mu     sync.Mutex
+	status ConsumerStatus
+}
+
+// supervisor 运行所有的消费者，消费者退出后按退避间隔重新连接并重新启动，所有消费者共用一个连接
+type supervisor struct {
+	mu        sync.Mutex
+	conn      *amqp.Connection
+	consumers []*consumer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// consumers 默认的监督者
+var consumers = newSupervisor()
+
+func newSupervisor() *supervisor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &supervisor{ctx: ctx, cancel: cancel}
+}
+
+// StartConsumer 在监督下启动消费者
+func StartConsumer(name, queue string, run ConsumerFunc) {
+	c := &consumer{run: run, status: ConsumerStatus{Name: name, Queue: queue, State: ConsumerStarting, Since: time.Now()}}
+	consumers.mu.Lock()
+	consumers.consumers = append(consumers.consumers, c)
+	consumers.mu.Unlock()
+
+	consumers.wg.Add(1)
+	go consumers.supervise(c)
+}
+
+// ConsumerStatuses 返回所有消费者的状态
+func ConsumerStatuses() []ConsumerStatus {
+	consumers.mu.Lock()
+	defer consumers.mu.Unlock()
+	list := make([]ConsumerStatus, 0, len(consumers.consumers))
+	for _, c := range consumers.consumers {
+		c.mu.Lock()
+		list = append(list, c.status)
+		c.mu.Unlock()
+	}
+	return list
+}
+
+// StopConsumers 通知所有消费者停止，并等待它们处理完已经收到的消息，超时后直接返回
+func StopConsumers() {
+	timeout := 30 * time.Second
+	if d, err := time.ParseDuration(global.Config.RabbitMQ.Consumer.StopTimeout); err == nil {
+		timeout = d
+	}
+
+	consumers.cancel()
+	done := make(chan struct{})
+	go func() {
+		consumers.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		global.Log.Info("RabbitMQ consumers stopped")
+	case <-time.After(timeout):
+		global.Log.Error("Timed out waiting for RabbitMQ consumers to stop", zap.Duration("timeout", timeout))
+	}
+
+	consumers.mu.Lock()
+	defer consumers.mu.Unlock()
+	// 初始连接由 main 关闭，这里只关闭重新连接后创建的连接
+	if consumers.conn != nil && consumers.conn != global.RmqConn {
+		consumers.conn.Close()
+	}
+}
+
+// Connection 返回消费者当前使用的连接，连接已断开时重新连接
+func Connection() (*amqp.Connection, error) {
+	return consumers.connection()
+}
+
+// supervise 运行消费者，退出后按退避间隔重新启动，直到监督者停止
+func (s *supervisor) supervise(c *consumer) {
+	defer s.wg.Done()
+	initial := time.Second
+	if d, err := time.ParseDuration(global.Config.RabbitMQ.Consumer.ReconnectInterval); err == nil && d > 0 {
+		initial = d
+	}
+	interval := initial
+
+	for {
+		conn, err := s.connection()
+		if err == nil {
+			c.setState(ConsumerRunning, nil)
+			started := time.Now()
+			err = c.run(s.ctx, conn)
+			// 正常运行过一段时间后再断开的，重新从初始间隔开始退避
+			if time.Since(started) > maxRetryInterval {
+				interval = initial
+			}
+		}
+		if s.ctx.Err() != nil {
+			c.setState(ConsumerStopped, err)
+			return
+		}
+		if err == nil {
+			err = errors.New("consumer returned unexpectedly")
+		}
+
+		c.setState(ConsumerReconnecting, err)
+		global.Log.Error("RabbitMQ consumer stopped, restarting", zap.String("consumer", c.status.Name), zap.Duration("after", interval), zap.Error(err))
+		select {
+		case <-s.ctx.Done():
+			c.setState(ConsumerStopped, err)
+			return
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxRetryInterval)
+		c.mu.Lock()
+		c.status.Restarts++
+		c.mu.Unlock()
+	}
+}
+
+// connection 返回当前的连接，没有连接或已断开时重新连接一次
+func (s *supervisor) connection() (*amqp.Connection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		s.conn = global.RmqConn
+	}
+	if s.conn != nil && !s.conn.IsClosed() {
+		return s.conn, nil
+	}
+
+	conn, err := amqp.Dial(global.Config.RabbitMQ.Dial)
+	if err != nil {
+		return nil, err
+	}
+	global.Log.Info("RabbitMQ consumer connection reconnected")
+	s.conn = conn
+	return conn, nil
+}
+
+// setState 更新消费者的状态
+func (c *consumer) setState(state ConsumerState, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.status.State != state {
+		c.status.State = state
+		c.status.Since = time.Now()
+	}
+	if err != nil {
+		c.status.LastError = err.Error()
+	}
+}
@@ -2,6 +2,8 @@ package router
 
 import (
 	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,7 +11,7 @@ import (
 type AdvertisementRouter struct{}
 
 func (a *AdvertisementRouter) InitAdvertisementRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup) {
-	advertisementRouter := Router.Group("advertisement")
+	advertisementRouter := Router.Group("advertisement").Use(middleware.Permission(appTypes.PermissionAdvertisementManage))
 	advertisementPublicRouter := PublicRouter.Group("advertisement")
 	advertisementApi := api.ApiGroupApp.AdvertisementApi
 	{
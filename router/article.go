@@ -2,6 +2,8 @@ package router
 
 import (
 	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,7 +13,7 @@ type ArticleRouter struct{}
 func (a *ArticleRouter) InitArticleRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup, AdminRouter *gin.RouterGroup) {
 	articleRouter := Router.Group("article")
 	articlePublic := PublicRouter.Group("article")
-	articleAdminRouter := AdminRouter.Group("article")
+	articleAdminRouter := middleware.Scoped(AdminRouter.Group("article", middleware.Permission(appTypes.PermissionArticleManage)), appTypes.ScopeArticleWrite)
 
 	articleApi := api.ApiGroupApp.ArticleApi
 	{
@@ -21,9 +23,20 @@ func (a *ArticleRouter) InitArticleRouter(Router *gin.RouterGroup, PublicRouter
 		articlePublic.GET("tags", articleApi.ArticleTags)
 	}
 	{
-		articleRouter.POST("like", articleApi.ArticleLike)
+		articleRouter.POST("like", middleware.RateLimit("article/like"), articleApi.ArticleLike)
 		articleRouter.GET("isLike", articleApi.ArticleIsLike)
 		articleRouter.GET("likesList", articleApi.ArticleLikesList)
+		articleRouter.GET("feed", articleApi.ArticleFeed)
+		articleRouter.GET("bookmarkFolders", articleApi.BookmarkFolders)
+		articleRouter.POST("bookmarkFolderCreate", articleApi.BookmarkFolderCreate)
+		articleRouter.PUT("bookmarkFolderUpdate", articleApi.BookmarkFolderUpdate)
+		articleRouter.DELETE("bookmarkFolderDelete", articleApi.BookmarkFolderDelete)
+		articleRouter.PUT("bookmarkMove", articleApi.BookmarkMove)
+		articleRouter.PUT("bookmarkNote", articleApi.BookmarkNote)
+		articleRouter.POST("readingBeacon", middleware.RateLimit("article/readingBeacon"), articleApi.ReadingBeacon)
+		articleRouter.GET("readingHistory", articleApi.ReadingHistory)
+		articleRouter.DELETE("readingHistoryClear", articleApi.ReadingHistoryClear)
+		articleRouter.GET("readingResume", articleApi.ReadingResume)
 	}
 	{
 		articleAdminRouter.POST("create", articleApi.ArticleCreate)
@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/gin-gonic/gin"
 	"server/api"
+	"server/middleware"
 )
 
 type baseRouter struct {
@@ -13,7 +14,10 @@ func (b *baseRouter) InitBaseRouter(Router *gin.RouterGroup) {
 	baseApi := api.ApiGroupApp.BaseApi
 	{
 		baseRouter.POST("captcha", baseApi.Captcha)
-		baseRouter.POST("sendEmailVerificationCode", baseApi.SendEmailVerificationCode)
+		baseRouter.POST("sendEmailVerificationCode", middleware.RateLimit("base/sendEmailVerificationCode"), baseApi.SendEmailVerificationCode)
 		baseRouter.GET("qqLoginURL", baseApi.QQLoginURL)
+		baseRouter.GET("oauthProviders", baseApi.OAuthProviders)
+		baseRouter.GET("oauthLoginURL", baseApi.OAuthLoginURL)
+		baseRouter.GET("jwks", baseApi.JWKS)
 	}
 }
@@ -2,6 +2,8 @@ package router
 
 import (
 	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,20 +12,24 @@ type CommentRouter struct{}
 
 func (a *CommentRouter) InitCommentRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup, AdminRouter *gin.RouterGroup) {
 	commentRouter := Router.Group("comment")
+	commentScopedRouter := middleware.Scoped(commentRouter, appTypes.ScopeCommentWrite)
 	commentPublicRouter := PublicRouter.Group("comment")
-	commentAdminRouter := AdminRouter.Group("comment")
+	commentAdminRouter := AdminRouter.Group("comment").Use(middleware.Permission(appTypes.PermissionCommentManage))
 
 	commentApi := api.ApiGroupApp.CommentApi
 	{
-		commentRouter.POST("create", commentApi.CommentCreate)
-		commentRouter.DELETE("delete", commentApi.CommentDelete)
+		commentScopedRouter.POST("create", middleware.RateLimit("comment/create"), commentApi.CommentCreate)
+		commentScopedRouter.DELETE("delete", commentApi.CommentDelete)
 		commentRouter.GET("info", commentApi.CommentInfo)
 	}
 	{
 		commentPublicRouter.GET(":article_id", commentApi.CommentInfoByArticleID)
 		commentPublicRouter.GET("new", commentApi.CommentNew)
+		commentPublicRouter.POST("guestCreate", middleware.RateLimit("comment/guestCreate"), commentApi.CommentGuestCreate)
+		commentPublicRouter.GET("guestVerify", commentApi.CommentGuestVerify)
 	}
 	{
 		commentAdminRouter.GET("list", commentApi.CommentList)
+		commentAdminRouter.PUT("review", commentApi.CommentReview)
 	}
 }
@@ -2,6 +2,8 @@ package router
 
 import (
 	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,7 +12,7 @@ type ConfigRouter struct {
 }
 
 func (c *ConfigRouter) InitConfigRouter(Router *gin.RouterGroup) {
-	configRouter := Router.Group("config")
+	configRouter := Router.Group("config").Use(middleware.Permission(appTypes.PermissionConfigManage))
 
 	configApi := api.ApiGroupApp.ConfigApi
 	{
@@ -26,6 +28,7 @@ func (c *ConfigRouter) InitConfigRouter(Router *gin.RouterGroup) {
 		configRouter.PUT("qiniu", configApi.UpdateQiniu)
 		configRouter.GET("jwt", configApi.GetJwt)
 		configRouter.PUT("jwt", configApi.UpdateJwt)
+		configRouter.POST("jwt/rotate", configApi.RotateJwtKeys)
 		configRouter.GET("gaode", configApi.GetGaode)
 		configRouter.PUT("gaode", configApi.UpdateGaode)
 	}
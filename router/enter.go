@@ -9,8 +9,11 @@ type RouterGroup struct {
 	AdvertisementRouter
 	FriendLinkRouter
 	FeedbackRouter
+	FollowRouter
 	ConfigRouter
 	WebsiteRouter
+	RBACRouter
+	QueueRouter
 }
 
 var RouterGroupApp = new(RouterGroup)
@@ -2,6 +2,8 @@ package router
 
 import (
 	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,11 +14,11 @@ type FeedbackRouter struct {
 func (f *FeedbackRouter) InitFeedbackRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup, AdminRouter *gin.RouterGroup) {
 	feedbackRouter := Router.Group("feedback")
 	feedbackPublicRouter := PublicRouter.Group("feedback")
-	feedbackAdminRouter := AdminRouter.Group("feedback")
+	feedbackAdminRouter := AdminRouter.Group("feedback").Use(middleware.Permission(appTypes.PermissionFeedbackManage))
 
 	feedbackApi := api.ApiGroupApp.FeedbackApi
 	{
-		feedbackRouter.POST("create", feedbackApi.FeedbackCreate)
+		feedbackRouter.POST("create", middleware.RateLimit("feedback/create"), feedbackApi.FeedbackCreate)
 		feedbackRouter.GET("info", feedbackApi.FeedbackInfo)
 	}
 	{
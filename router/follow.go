@@ -0,0 +1,26 @@
+package router
+
+import (
+	"server/api"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowRouter struct {
+}
+
+func (f *FollowRouter) InitFollowRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup) {
+	followRouter := Router.Group("follow")
+	followPublicRouter := PublicRouter.Group("follow")
+	followApi := api.ApiGroupApp.FollowApi
+	{
+		followRouter.POST("create", followApi.FollowCreate)
+		followRouter.DELETE("delete", followApi.FollowDelete)
+		followRouter.GET("isFollow", followApi.FollowIs)
+		followRouter.GET("list", followApi.FollowList)
+	}
+	{
+		followPublicRouter.GET("followers", followApi.Followers)
+		followPublicRouter.GET("following", followApi.Following)
+	}
+}
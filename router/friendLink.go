@@ -2,6 +2,8 @@ package router
 
 import (
 	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,7 +11,7 @@ import (
 type FriendLinkRouter struct{}
 
 func (a *FriendLinkRouter) InitFriendLinkRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup) {
-	FriendLinkRouter := Router.Group("friendLink")
+	FriendLinkRouter := Router.Group("friendLink").Use(middleware.Permission(appTypes.PermissionFriendLinkManage))
 	FriendLinkPublicRouter := PublicRouter.Group("friendLink")
 	FriendLinkApi := api.ApiGroupApp.FriendLinkApi
 	{
@@ -3,18 +3,21 @@ package router
 import (
 	"github.com/gin-gonic/gin"
 	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
 )
 
 type ImageRouter struct {
 }
 
 func (i *ImageRouter) InitImageRouter(Router *gin.RouterGroup) {
-	imageRouter := Router.Group("image")
+	imageGroup := Router.Group("image", middleware.Permission(appTypes.PermissionImageManage))
+	imageRouter := middleware.Scoped(imageGroup, appTypes.ScopeImageUpload)
 
 	imageApi := api.ApiGroupApp.ImageApi
 	{
 		imageRouter.POST("upload", imageApi.ImageUpload)
-		imageRouter.DELETE("delete", imageApi.ImageDelete)
+		imageGroup.DELETE("delete", imageApi.ImageDelete)
 		imageRouter.GET("list", imageApi.ImageList)
 	}
 }
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QueueRouter struct {
+}
+
+func (q *QueueRouter) InitQueueRouter(Router *gin.RouterGroup) {
+	queueRouter := Router.Group("queue").Use(middleware.Permission(appTypes.PermissionQueueManage))
+
+	queueApi := api.ApiGroupApp.QueueApi
+	{
+		queueRouter.GET("consumers", queueApi.ConsumerStatuses)
+		queueRouter.GET("deadLetters", queueApi.DeadLetterList)
+		queueRouter.POST("deadLetterReplay", queueApi.DeadLetterReplay)
+	}
+}
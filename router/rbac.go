@@ -0,0 +1,26 @@
+package router
+
+import (
+	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RBACRouter struct {
+}
+
+func (r *RBACRouter) InitRBACRouter(Router *gin.RouterGroup) {
+	rbacRouter := Router.Group("rbac").Use(middleware.Permission(appTypes.PermissionRoleManage))
+	rbacApi := api.ApiGroupApp.RBACApi
+	{
+		rbacRouter.GET("permissions", rbacApi.PermissionList)
+		rbacRouter.GET("roles", rbacApi.RoleList)
+		rbacRouter.POST("roleCreate", rbacApi.RoleCreate)
+		rbacRouter.PUT("roleUpdate", rbacApi.RoleUpdate)
+		rbacRouter.DELETE("roleDelete", rbacApi.RoleDelete)
+		rbacRouter.GET("userRoles", rbacApi.UserRoles)
+		rbacRouter.PUT("userRoles", rbacApi.UserRolesUpdate)
+	}
+}
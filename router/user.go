@@ -3,6 +3,7 @@ package router
 import (
 	"server/api"
 	"server/middleware"
+	"server/model/appTypes"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,30 +12,57 @@ type UserRouter struct{}
 
 func (u *UserRouter) InitUserRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup, AdminRouter *gin.RouterGroup) {
 	userRouter := Router.Group("user")
+	userScopedRouter := middleware.Scoped(userRouter, appTypes.ScopeUserRead)
 	userPublicRouter := PublicRouter.Group("user")
 	userLoginRouter := PublicRouter.Group("user").Use(middleware.LoginRecord())
-	userAdminRouter := AdminRouter.Group("user")
+	userAdminRouter := AdminRouter.Group("user").Use(middleware.Permission(appTypes.PermissionUserManage))
 	userApi := api.ApiGroupApp.UserApi
 	{
 		userRouter.POST("logout", userApi.Logout)
 		userRouter.PUT("resetPassword", userApi.UserResetPassword)
-		userRouter.GET("info", userApi.UserInfo)
+		userScopedRouter.GET("info", userApi.UserInfo)
+		userScopedRouter.GET("permissions", userApi.UserPermissions)
 		userRouter.PUT("changeInfo", userApi.UserChangeInfo)
+		userRouter.PUT("changeEmail", userApi.UserChangeEmail)
+		userRouter.POST("deletionRequest", userApi.UserDeletionRequest)
+		userRouter.DELETE("deletionCancel", userApi.UserDeletionCancel)
+		userRouter.POST("export", middleware.RateLimit("user/export"), userApi.UserExport)
 		userRouter.GET("weather", userApi.UserWeather)
 		userRouter.GET("chart", userApi.UserChart)
+		userRouter.GET("oauthLinkURL", userApi.OAuthLinkURL)
+		userRouter.POST("oauthLink", userApi.OAuthLink)
+		userRouter.DELETE("oauthUnlink", userApi.OAuthUnlink)
+		userRouter.GET("oauthIdentities", userApi.OAuthIdentities)
+		userRouter.GET("twoFactor", userApi.TwoFactorStatus)
+		userRouter.POST("twoFactor/enroll", userApi.TwoFactorEnroll)
+		userRouter.POST("twoFactor/confirm", userApi.TwoFactorConfirm)
+		userRouter.POST("twoFactor/disable", userApi.TwoFactorDisable)
+		userRouter.GET("sessionList", userApi.SessionList)
+		userRouter.DELETE("sessionRevoke", userApi.SessionRevoke)
+		userRouter.DELETE("sessionRevokeOthers", userApi.SessionRevokeOthers)
+		userRouter.GET("tokenScopes", userApi.TokenScopes)
+		userRouter.GET("tokenList", userApi.TokenList)
+		userRouter.POST("tokenCreate", userApi.TokenCreate)
+		userRouter.DELETE("tokenRevoke", userApi.TokenRevoke)
 	}
 	{
 		userPublicRouter.POST("forgotPassword", userApi.ForgotPassword)
+		userPublicRouter.POST("passwordResetRequest", middleware.RateLimit("user/passwordResetRequest"), userApi.PasswordResetRequest)
+		userPublicRouter.POST("passwordReset", middleware.RateLimit("user/passwordReset"), userApi.PasswordReset)
+		userPublicRouter.POST("loginAlertReject", middleware.RateLimit("user/loginAlertReject"), userApi.LoginAlertReject)
 		userPublicRouter.GET("card", userApi.UserCard)
+		userPublicRouter.GET("exportDownload", userApi.UserExportDownload)
 	}
 	{
 		userLoginRouter.POST("register", userApi.Register)
-		userLoginRouter.POST("login", userApi.Login)
+		userLoginRouter.POST("login", middleware.RateLimit("user/login"), userApi.Login)
+		userLoginRouter.POST("twoFactorVerify", middleware.RateLimit("user/twoFactorVerify"), userApi.TwoFactorVerify)
 	}
 	{
 		userAdminRouter.GET("list", userApi.UserList)
 		userAdminRouter.PUT("freeze", userApi.UserFreeze)
 		userAdminRouter.PUT("unfreeze", userApi.UserUnfreeze)
+		userAdminRouter.PUT("unlock", userApi.UserUnlock)
 		userAdminRouter.GET("loginList", userApi.UserLoginList)
 	}
 }
@@ -2,6 +2,8 @@ package router
 
 import (
 	"server/api"
+	"server/middleware"
+	"server/model/appTypes"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,7 +12,7 @@ type WebsiteRouter struct {
 }
 
 func (w *WebsiteRouter) InitWebsiteRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup) {
-	websiteRouter := Router.Group("website")
+	websiteRouter := Router.Group("website").Use(middleware.Permission(appTypes.PermissionWebsiteManage))
 	websitePublicRouter := PublicRouter.Group("website")
 
 	websiteApi := api.ApiGroupApp.WebsiteApi
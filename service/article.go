@@ -4,13 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"server/eventbus"
 	"server/global"
 	"server/model/appTypes"
 	"server/model/database"
 	"server/model/elasticsearch"
 	"server/model/other"
 	"server/model/request"
-	"server/rabbitmq"
+	"server/model/response"
 	"server/utils"
 	"time"
 
@@ -28,13 +29,13 @@ func (articleService *ArticleService) ArticleInfoByID(req request.ArticleInfoByI
 	go func() {
 		// articleView := articleService.NewArticleView()
 		// _ = articleView.Set(req.ID)
-		event := rabbitmq.ESEvent{
+		event := eventbus.ESEvent{
 			ArticleID: req.ID,
 			Field:     "views",
 			Delta:     1,
 		}
 		msgBytes, _ := json.Marshal(event)
-		err := rabbitmq.PublishMessage(global.RmqConn, "es_update_queue", msgBytes)
+		err := global.EventBus.Publish(eventbus.TopicESUpdate, msgBytes)
 		if err != nil {
 			global.Log.Error("Failed to publish message:", zap.Error(err))
 		}
@@ -148,7 +149,14 @@ func (articleService *ArticleService) ArticleLike(req request.ArticleLike) error
 		var articleLike database.ArticleLike
 		var num int
 		if errors.Is(tx.Where("user_id = ? AND article_id = ?", req.UserID, req.ArticleID).First(&articleLike).Error, gorm.ErrRecordNotFound) {
-			if err := tx.Create(&database.ArticleLike{UserID: req.UserID, ArticleID: req.ArticleID}).Error; err != nil {
+			articleLike = database.ArticleLike{UserID: req.UserID, ArticleID: req.ArticleID}
+			if req.FolderID != 0 {
+				if err := articleService.checkBookmarkFolder(tx, req.UserID, req.FolderID); err != nil {
+					return err
+				}
+				articleLike.FolderID = &req.FolderID
+			}
+			if err := tx.Create(&articleLike).Error; err != nil {
 				return err
 			}
 			num = 1
@@ -164,13 +172,13 @@ func (articleService *ArticleService) ArticleLike(req request.ArticleLike) error
 		// _, err := global.ESClient.Update(elasticsearch.ArticleIndex(), req.ArticleID).Script(&script).Do(context.TODO())
 
 		// 发送消息到 RabbitMQ
-		event := rabbitmq.ESEvent{
+		event := eventbus.ESEvent{
 			ArticleID: req.ArticleID,
 			Field:     "likes",
 			Delta:     num,
 		}
 		msgBytes, _ := json.Marshal(event)
-		err := rabbitmq.PublishMessage(global.RmqConn, "es_update_queue", msgBytes)
+		err := global.EventBus.Publish(eventbus.TopicESUpdate, msgBytes)
 		return err
 	})
 }
@@ -188,6 +196,13 @@ func (articleService *ArticleService) ArticleIsLike(req request.ArticleLike) (bo
 
 func (articleService *ArticleService) ArticleLikesList(info request.ArticleLikesList) (interface{}, int64, error) {
 	where := global.DB.Where("user_id = ?", info.UserID)
+	if info.FolderID != nil {
+		if *info.FolderID == 0 {
+			where = where.Where("folder_id IS NULL")
+		} else {
+			where = where.Where("folder_id = ?", *info.FolderID)
+		}
+	}
 	option := other.MySQLOption{
 		PageInfo: info.PageInfo,
 		Where:    where,
@@ -196,21 +211,28 @@ func (articleService *ArticleService) ArticleLikesList(info request.ArticleLikes
 	if err != nil {
 		return nil, 0, err
 	}
-	var list []struct {
-		Id_     string                `json:"_id"`
-		Source_ elasticsearch.Article `json:"_source"`
+
+	// 一次性获取本页收藏的所有文章，已被删除的文章不再返回
+	ids := make([]string, 0, len(l))
+	for _, v := range l {
+		ids = append(ids, v.ArticleID)
 	}
+	articles, err := articleService.MGet(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	list := make([]response.Bookmark, 0, len(l))
 	for _, v := range l {
-		article, err := articleService.Get(v.ArticleID)
-		if err != nil {
-			return nil, 0, err
+		article, ok := articles[v.ArticleID]
+		if !ok {
+			continue
 		}
-		list = append(list, struct {
-			Id_     string                `json:"_id"`
-			Source_ elasticsearch.Article `json:"_source"`
-		}{
-			Id_:     v.ArticleID,
-			Source_: article,
+		list = append(list, response.Bookmark{
+			Id_:       v.ArticleID,
+			Source_:   article,
+			FolderID:  v.FolderID,
+			Note:      v.Note,
+			CreatedAt: v.CreatedAt,
 		})
 	}
 	return list, total, nil
@@ -227,15 +249,16 @@ func (articleService *ArticleService) ArticleCreate(req request.ArticleCreate) e
 	}
 	now := time.Now().Format("2006-01-02 15:04:05")
 	articleToCreate := &elasticsearch.Article{
-		Title:     req.Title,
-		Cover:     req.Cover,
-		Category:  req.Category,
-		Tags:      req.Tags,
-		Abstract:  req.Abstract,
-		Content:   req.Content,
-		CreatedAt: now,
-		UpdatedAt: now,
-		Keyword:   req.Title,
+		Title:      req.Title,
+		Cover:      req.Cover,
+		Category:   req.Category,
+		Tags:       req.Tags,
+		Abstract:   req.Abstract,
+		Content:    req.Content,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Keyword:    req.Title,
+		AuthorUUID: req.AuthorUUID,
 	}
 	return global.DB.Transaction(func(tx *gorm.DB) error {
 		// 同时更新文章类别表中的数据
@@ -414,3 +437,49 @@ func (article *ArticleService) ArticleList(info request.ArticleList) (list inter
 	}
 	return utils.EsPagination(context.TODO(), options)
 }
+
+// ArticleFeed 获取关注的作者、标签和类别下的文章，按发布时间倒序排列
+func (articleService *ArticleService) ArticleFeed(info request.ArticleFeed) (list interface{}, total int64, err error) {
+	authors, tags, categories, err := ServiceGroupApp.FollowService.feedTargets(info.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(authors) == 0 && len(tags) == 0 && len(categories) == 0 {
+		return []types.Hit{}, 0, nil
+	}
+
+	boolQuery := &types.BoolQuery{MinimumShouldMatch: 1}
+	for _, terms := range []struct {
+		field  string
+		values []string
+	}{
+		{"author_uuid", authors},
+		{"tags", tags},
+		{"category", categories},
+	} {
+		if len(terms.values) > 0 {
+			boolQuery.Should = append(boolQuery.Should, types.Query{
+				Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{terms.field: terms.values}},
+			})
+		}
+	}
+
+	order := sortorder.Desc
+	req := search.Request{
+		Query: &types.Query{Bool: boolQuery},
+		Sort: []types.SortCombinations{
+			types.SortOptions{
+				SortOptions: map[string]types.FieldSort{
+					"created_at": {Order: &order},
+				},
+			},
+		},
+	}
+	option := other.EsOption{
+		PageInfo:       info.PageInfo,
+		Index:          elasticsearch.ArticleIndex(),
+		Request:        &req,
+		SourceIncludes: []string{"created_at", "cover", "title", "abstract", "category", "tags", "views", "comments", "likes", "author_uuid"},
+	}
+	return utils.EsPagination(context.TODO(), option)
+}
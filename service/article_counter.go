@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/bulk"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/refresh"
+	"gorm.io/gorm"
+)
+
+// ArticleCounterReconcile 根据 MySQL 中的 ArticleLike 和 Comment 重新计算 ES 中文章的 likes 和 comments，
+// 将不一致的值改写为重新计算的结果，并返回所有被纠正的差异
+func (articleService *ArticleService) ArticleCounterReconcile() ([]other.CounterDiscrepancy, error) {
+	likes, err := articleService.countByArticle(global.DB.Model(&database.ArticleLike{}))
+	if err != nil {
+		return nil, err
+	}
+	comments, err := articleService.countByArticle(global.DB.Model(&database.Comment{}).Where("status = ?", appTypes.Published))
+	if err != nil {
+		return nil, err
+	}
+
+	var discrepancies []other.CounterDiscrepancy
+	var request bulk.Request
+
+	// 使用 Scroll API 遍历所有文章，只取出计数字段
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Scroll("1m").
+		Size(1000).
+		SourceIncludes_("likes", "comments").
+		Query(&types.Query{MatchAll: &types.MatchAllQuery{}}).
+		Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	scrollID := res.ScrollId_
+	hits := res.Hits.Hits
+	for len(hits) > 0 {
+		for _, hit := range hits {
+			var counter struct {
+				Likes    int `json:"likes"`
+				Comments int `json:"comments"`
+			}
+			if err := json.Unmarshal(hit.Source_, &counter); err != nil {
+				return nil, err
+			}
+
+			id := *hit.Id_
+			doc := make(map[string]int)
+			if counter.Likes != likes[id] {
+				discrepancies = append(discrepancies, other.CounterDiscrepancy{ArticleID: id, Field: "likes", Old: counter.Likes, New: likes[id]})
+				doc["likes"] = likes[id]
+			}
+			if counter.Comments != comments[id] {
+				discrepancies = append(discrepancies, other.CounterDiscrepancy{ArticleID: id, Field: "comments", Old: counter.Comments, New: comments[id]})
+				doc["comments"] = comments[id]
+			}
+			if len(doc) == 0 {
+				continue
+			}
+			docBytes, err := json.Marshal(doc)
+			if err != nil {
+				return nil, err
+			}
+			request = append(request, types.OperationContainer{Update: &types.UpdateOperation{Id_: &id}}, types.UpdateAction{Doc: docBytes})
+		}
+
+		scrollRes, err := global.ESClient.Scroll().ScrollId(*scrollID).Scroll("1m").Do(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		scrollID = scrollRes.ScrollId_
+		hits = scrollRes.Hits.Hits
+	}
+
+	// 清除滚动查询，释放 Elasticsearch 上的资源
+	if _, err := global.ESClient.ClearScroll().ScrollId(*scrollID).Do(context.TODO()); err != nil {
+		return nil, err
+	}
+
+	if len(request) == 0 {
+		return discrepancies, nil
+	}
+	if _, err := global.ESClient.Bulk().Request(&request).Index(elasticsearch.ArticleIndex()).Refresh(refresh.True).Do(context.TODO()); err != nil {
+		return nil, err
+	}
+	return discrepancies, nil
+}
+
+// countByArticle 按文章 ID 分组统计记录数
+func (articleService *ArticleService) countByArticle(db *gorm.DB) (map[string]int, error) {
+	var rows []struct {
+		ArticleID string
+		Count     int
+	}
+	if err := db.Select("article_id, count(*) AS count").Group("article_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.ArticleID] = row.Count
+	}
+	return counts, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"server/global"
 	"server/model/database"
 	"server/model/elasticsearch"
@@ -14,6 +15,7 @@ import (
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/update"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/refresh"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
 	"gorm.io/gorm"
 )
 
@@ -47,6 +49,30 @@ func (articleService *ArticleService) Get(id string) (elasticsearch.Article, err
 	return a, err
 }
 
+// MGet 用于通过多个ID从Elasticsearch 一次性获取文章，不存在的文章不会出现在结果中
+func (articleService *ArticleService) MGet(ids []string) (map[string]elasticsearch.Article, error) {
+	articles := make(map[string]elasticsearch.Article, len(ids))
+	if len(ids) == 0 {
+		return articles, nil
+	}
+	res, err := global.ESClient.Mget().Index(elasticsearch.ArticleIndex()).Ids(ids...).Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range res.Docs {
+		result, ok := doc.(*types.GetResult)
+		if !ok || !result.Found {
+			continue
+		}
+		var a elasticsearch.Article
+		if err := json.Unmarshal(result.Source_, &a); err != nil {
+			return nil, err
+		}
+		articles[result.Id_] = a
+	}
+	return articles, nil
+}
+
 // Update 用于更新文章数据
 func (articleService *ArticleService) Update(articleID string, v any) error {
 	// 将待更新的值转换为 JSON
@@ -154,3 +180,37 @@ func (articleService *ArticleService) UpdateTagsCount(tx *gorm.DB, oldTags, newT
 	}
 	return nil
 }
+
+// ArticleAuthorBackfill 为没有作者的文章设置作者，关注作者功能上线前创建的文章没有记录作者，返回更新的文章数量
+func (articleService *ArticleService) ArticleAuthorBackfill(email string) (int64, error) {
+	var user database.User
+	if err := global.DB.Where("email = ?", email).First(&user).Error; err != nil {
+		return 0, fmt.Errorf("failed to find the author %s: %w", email, err)
+	}
+	authorUUID, err := json.Marshal(user.UUID.String())
+	if err != nil {
+		return 0, err
+	}
+
+	// 旧文章没有 author_uuid 字段，从导出文件导入的旧文章 author_uuid 为空字符串
+	source := "ctx._source.author_uuid = params.author_uuid"
+	res, err := global.ESClient.UpdateByQuery(elasticsearch.ArticleIndex()).
+		Query(&types.Query{Bool: &types.BoolQuery{Should: []types.Query{
+			{Bool: &types.BoolQuery{MustNot: []types.Query{{Exists: &types.ExistsQuery{Field: "author_uuid"}}}}},
+			{Term: map[string]types.TermQuery{"author_uuid": {Value: ""}}},
+		}}}).
+		Script(&types.Script{Source: &source, Lang: &scriptlanguage.Painless, Params: map[string]json.RawMessage{"author_uuid": authorUUID}}).
+		Refresh(true).
+		Do(context.TODO())
+	if err != nil {
+		return 0, err
+	}
+	var updated int64
+	if res.Updated != nil {
+		updated = *res.Updated
+	}
+	if len(res.Failures) > 0 {
+		return updated, fmt.Errorf("failed to update %d articles", len(res.Failures))
+	}
+	return updated, nil
+}
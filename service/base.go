@@ -1,36 +1,88 @@
 package service
 
 import (
-	"github.com/gin-contrib/sessions"
-	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
+	"crypto/subtle"
+	"errors"
+	"fmt"
 	"server/global"
+	"server/model/database"
 	"server/utils"
+	"strings"
 	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type BaseService struct{}
 
-func (baseService *BaseService) SendEmailVerificationCode(c *gin.Context, to string) error {
+// 邮箱验证码的用途
+const (
+	EmailCodeRegister    = "register"     // 注册
+	EmailCodeReset       = "reset"        // 找回密码
+	EmailCodeChangeEmail = "change-email" // 修改邮箱
+)
+
+const (
+	emailCodeTTL         = 5 * time.Minute // 验证码有效期
+	emailCodeMaxAttempts = 5               // 每个验证码允许的最大尝试次数
+	emailCodeCooldown    = time.Minute     // 同一邮箱两次发送之间的最短间隔
+	emailCodeEmailLimit  = 10              // 同一邮箱每小时最多发送次数
+	emailCodeIPLimit     = 30              // 同一 IP 每小时最多发送次数
+)
+
+var emailCodeSubjects = map[string]string{
+	EmailCodeRegister:    "注册",
+	EmailCodeReset:       "找回密码",
+	EmailCodeChangeEmail: "修改邮箱",
+}
+
+// SendEmailVerificationCode 向邮箱发送指定用途的验证码，验证码保存在 Redis 中；
+// purpose 为空时，邮箱未注册则用于注册，否则用于找回密码
+func (baseService *BaseService) SendEmailVerificationCode(to, purpose, ip string) error {
+	to = strings.TrimSpace(to)
+	if purpose == "" {
+		purpose = EmailCodeReset
+		if errors.Is(global.DB.Where("email = ?", to).First(&database.User{}).Error, gorm.ErrRecordNotFound) {
+			purpose = EmailCodeRegister
+		}
+	}
+	if _, ok := emailCodeSubjects[purpose]; !ok {
+		return fmt.Errorf("unknown verification code purpose: %s", purpose)
+	}
+
+	// 限制发送频率：同一邮箱的发送间隔，以及同一邮箱和同一 IP 每小时的发送次数
+	email := normalizeEmail(to)
+	if ok, err := global.Redis.SetNX("email_code_cooldown:"+email, 1, emailCodeCooldown).Result(); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("verification codes are sent too frequently, please try again later")
+	}
+	rateLimitService := ServiceGroupApp.RateLimitService
+	for key, limit := range map[string]int{"email_code:email:" + email: emailCodeEmailLimit, "email_code:ip:" + ip: emailCodeIPLimit} {
+		allowed, _, err := rateLimitService.Allow(key, limit, time.Hour)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.New("too many verification codes have been requested, please try again later")
+		}
+	}
+
 	verificationCode := utils.GenerateVerificationCode(6)
-	expireTime := time.Now().Add(5 * time.Minute).Unix()
-
-	//把验证码，验证邮箱，过期时间存到session中
-	session := sessions.Default(c)
-	session.Set("verificationCode", verificationCode)
-	session.Set("expiretime", expireTime)
-	session.Set("email", to)
-	err := session.Save()
-	//global.Log.Info("session",zap.String("ID:",session.ID()))
-	if err != nil {
-		global.Log.Error("failed to save session", zap.Error(err))
+	key := emailCodeKey(email, purpose)
+	if err := global.Redis.HMSet(key, map[string]interface{}{
+		"hash":     utils.SHA256Hex(verificationCode),
+		"attempts": 0,
+	}).Err(); err != nil {
 		return err
 	}
+	global.Redis.Expire(key, emailCodeTTL)
 
-	subject := "您的邮箱验证码"
+	subject := "您的" + emailCodeSubjects[purpose] + "验证码"
 	body := `亲爱的用户[` + to + `]，<br/>
 <br/>
-感谢您注册` + global.Config.Website.Name + `的个人博客！为了确保您的邮箱安全，请使用以下验证码进行验证：<br/>
+您正在` + global.Config.Website.Name + `的个人博客进行` + emailCodeSubjects[purpose] + `操作。为了确保您的邮箱安全，请使用以下验证码进行验证：<br/>
 <br/>
 验证码：[<font color="blue"><u>` + verificationCode + `</u></font>]<br/>
 该验证码在 5 分钟内有效，请尽快使用。<br/>
@@ -44,7 +96,48 @@ func (baseService *BaseService) SendEmailVerificationCode(c *gin.Context, to str
 		global.Config.Website.Title + `<br/>
 <br/>`
 
-	_ = utils.Email(to, subject, body)
+	if err := utils.Email(to, subject, body); err != nil {
+		global.Log.Error("Failed to send verification code email", zap.Error(err))
+	}
+	return nil
+}
+
+// VerifyEmailCode 校验指定邮箱和用途的验证码，校验成功后验证码立即失效，失败次数过多时验证码作废
+func (baseService *BaseService) VerifyEmailCode(email, purpose, code string) error {
+	key := emailCodeKey(normalizeEmail(email), purpose)
+	data, err := global.Redis.HGetAll(key).Result()
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("the verification code has expired, please resend it")
+	}
 
+	attempts, err := global.Redis.HIncrBy(key, "attempts", 1).Result()
+	if err != nil {
+		return err
+	}
+	if attempts > emailCodeMaxAttempts {
+		global.Redis.Del(key)
+		return errors.New("too many attempts, please resend the verification code")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(data["hash"]), []byte(utils.SHA256Hex(code))) != 1 {
+		return errors.New("invalid verification code")
+	}
+
+	// 删除成功的请求才算校验通过，保证验证码只能使用一次
+	deleted, err := global.Redis.Del(key).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("the verification code has expired, please resend it")
+	}
 	return nil
 }
+
+// emailCodeKey 邮箱验证码在 Redis 中的键
+func emailCodeKey(email, purpose string) string {
+	return "email_code:" + purpose + ":" + email
+}
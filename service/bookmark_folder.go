@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"server/global"
+	"server/model/database"
+	"server/model/request"
+	"server/model/response"
+
+	"gorm.io/gorm"
+)
+
+// maxBookmarkFolders 每个用户最多可以创建的收藏夹数量
+const maxBookmarkFolders = 50
+
+// BookmarkFolderList 获取用户的所有收藏夹及每个收藏夹中的文章数量，ID 为 0 的收藏夹表示未分类
+func (articleService *ArticleService) BookmarkFolderList(userID uint) ([]response.BookmarkFolder, error) {
+	var folders []database.BookmarkFolder
+	if err := global.DB.Where("user_id = ?", userID).Order("id").Find(&folders).Error; err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		FolderID *uint
+		Count    int64
+	}
+	if err := global.DB.Model(&database.ArticleLike{}).Where("user_id = ?", userID).
+		Select("folder_id, count(*) AS count").Group("folder_id").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	countByFolder := make(map[uint]int64, len(counts))
+	for _, c := range counts {
+		var id uint
+		if c.FolderID != nil {
+			id = *c.FolderID
+		}
+		countByFolder[id] = c.Count
+	}
+
+	list := make([]response.BookmarkFolder, 0, len(folders)+1)
+	list = append(list, response.BookmarkFolder{
+		BookmarkFolder: database.BookmarkFolder{UserID: userID, Name: "未分类"},
+		Count:          countByFolder[0],
+	})
+	for _, folder := range folders {
+		list = append(list, response.BookmarkFolder{BookmarkFolder: folder, Count: countByFolder[folder.ID]})
+	}
+	return list, nil
+}
+
+// BookmarkFolderCreate 创建收藏夹
+func (articleService *ArticleService) BookmarkFolderCreate(req request.BookmarkFolderCreate) (database.BookmarkFolder, error) {
+	var count int64
+	if err := global.DB.Model(&database.BookmarkFolder{}).Where("user_id = ?", req.UserID).Count(&count).Error; err != nil {
+		return database.BookmarkFolder{}, err
+	}
+	if count >= maxBookmarkFolders {
+		return database.BookmarkFolder{}, errors.New("you have reached the maximum number of bookmark folders")
+	}
+	folder := database.BookmarkFolder{UserID: req.UserID, Name: req.Name, Description: req.Description}
+	return folder, global.DB.Create(&folder).Error
+}
+
+// BookmarkFolderUpdate 修改收藏夹名称和描述
+func (articleService *ArticleService) BookmarkFolderUpdate(req request.BookmarkFolderUpdate) error {
+	if err := articleService.checkBookmarkFolder(global.DB, req.UserID, req.ID); err != nil {
+		return err
+	}
+	return global.DB.Model(&database.BookmarkFolder{}).Where("id = ? AND user_id = ?", req.ID, req.UserID).
+		Updates(map[string]interface{}{"name": req.Name, "description": req.Description}).Error
+}
+
+// BookmarkFolderDelete 删除收藏夹，其中的收藏会移到未分类
+func (articleService *ArticleService) BookmarkFolderDelete(req request.BookmarkFolderDelete) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := articleService.checkBookmarkFolder(tx, req.UserID, req.ID); err != nil {
+			return err
+		}
+		if err := tx.Model(&database.ArticleLike{}).Where("user_id = ? AND folder_id = ?", req.UserID, req.ID).
+			Update("folder_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ? AND user_id = ?", req.ID, req.UserID).Delete(&database.BookmarkFolder{}).Error
+	})
+}
+
+// BookmarkMove 把多篇收藏移动到指定收藏夹，FolderID 为 0 时移到未分类
+func (articleService *ArticleService) BookmarkMove(req request.BookmarkMove) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		var folderID *uint
+		if req.FolderID != 0 {
+			if err := articleService.checkBookmarkFolder(tx, req.UserID, req.FolderID); err != nil {
+				return err
+			}
+			folderID = &req.FolderID
+		}
+		return tx.Model(&database.ArticleLike{}).Where("user_id = ? AND article_id IN ?", req.UserID, req.ArticleIDs).
+			Update("folder_id", folderID).Error
+	})
+}
+
+// BookmarkNote 修改收藏的私人备注
+func (articleService *ArticleService) BookmarkNote(req request.BookmarkNote) error {
+	var articleLike database.ArticleLike
+	if err := global.DB.Where("user_id = ? AND article_id = ?", req.UserID, req.ArticleID).First(&articleLike).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("the article has not been bookmarked")
+		}
+		return err
+	}
+	return global.DB.Model(&articleLike).Update("note", req.Note).Error
+}
+
+// checkBookmarkFolder 检查收藏夹是否存在并属于该用户
+func (articleService *ArticleService) checkBookmarkFolder(tx *gorm.DB, userID, folderID uint) error {
+	if errors.Is(tx.Where("id = ? AND user_id = ?", folderID, userID).First(&database.BookmarkFolder{}).Error, gorm.ErrRecordNotFound) {
+		return errors.New("the bookmark folder does not exist")
+	}
+	return nil
+}
@@ -45,7 +45,8 @@ func (configService *ConfigService) UpdateWebsite(website config.Website) error
 }
 
 func (configService *ConfigService) UpdateSystem(system config.System) error {
-	global.Config.System.UseMultipoint = system.UseMultipoint
+	global.Config.System.MaxSessions = system.MaxSessions
+	global.Config.System.RequireAdmin2FA = system.RequireAdmin2FA
 	global.Config.System.SessionsSecret = system.SessionsSecret
 	global.Config.System.OssType = system.OssType
 	return utils.SaveYAML()
@@ -68,7 +69,11 @@ func (configService *ConfigService) UpdateQiniu(qiniu config.Qiniu) error {
 
 func (configService *ConfigService) UpdateJwt(jwt config.Jwt) error {
 	global.Config.Jwt = jwt
-	return utils.SaveYAML()
+	if err := utils.SaveYAML(); err != nil {
+		return err
+	}
+	// 签名算法发生变化时立即轮换密钥
+	return ServiceGroupApp.JwtKeyService.RotateJwtKeysIfDue()
 }
 
 func (configService *ConfigService) UpdateGaode(gaode config.Gaode) error {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -20,7 +21,7 @@ type CommentService struct {
 func (commentService *CommentService) CommentInfoByArticleID(req request.CommentInfoByArticleID) (interface{}, error) {
 	var comments []database.Comment
 	// 查找指定文章的一级评论
-	if err := global.DB.Where("article_id = ? AND p_id IS NULL", req.ArticleID).Preload("User", func(db *gorm.DB) *gorm.DB {
+	if err := global.DB.Where("article_id = ? AND p_id IS NULL AND status = ?", req.ArticleID, appTypes.Published).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("uuid, username, avatar, address, signature")
 	}).Find(&comments).Error; err != nil {
 		return nil, err
@@ -37,7 +38,7 @@ func (commentService *CommentService) CommentInfoByArticleID(req request.Comment
 
 func (commentService *CommentService) CommentNew() (list []database.Comment, err error) {
 	var comments []database.Comment
-	err = global.DB.Order("id desc").Limit(5).Preload("User", func(db *gorm.DB) *gorm.DB {
+	err = global.DB.Where("status = ?", appTypes.Published).Order("id desc").Limit(5).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("uuid, username, avatar, address, signature")
 	}).Find(&comments).Error
 	if err != nil {
@@ -50,7 +51,7 @@ func (commentService *CommentService) CommentCreate(req request.CommentCreate) e
 	comment := database.Comment{
 		ArticleID: req.ArticleID,
 		PID:       req.PID,
-		UserUUID:  req.UserUUID,
+		UserUUID:  &req.UserUUID,
 		Content:   req.Content,
 	}
 	if err := global.DB.Create(&comment).Error; err != nil {
@@ -59,6 +60,89 @@ func (commentService *CommentService) CommentCreate(req request.CommentCreate) e
 	return nil
 }
 
+func (commentService *CommentService) CommentGuestCreate(req request.CommentGuestCreate) (appTypes.CommentStatus, error) {
+	commentCfg := global.Config.Comment
+	if !commentCfg.GuestEnable {
+		return 0, errors.New("guest comments are disabled, please log in first")
+	}
+
+	status := appTypes.Published
+	if commentCfg.GuestEmailVerify {
+		status = appTypes.Unverified
+	} else if commentCfg.GuestModeration {
+		status = appTypes.Pending
+	}
+
+	comment := database.Comment{
+		ArticleID:     req.ArticleID,
+		PID:           req.PID,
+		GuestNickname: req.Nickname,
+		GuestEmail:    req.Email,
+		Content:       req.Content,
+		Status:        status,
+	}
+	if err := global.DB.Create(&comment).Error; err != nil {
+		return 0, err
+	}
+
+	if status == appTypes.Unverified {
+		// 验证邮件发送失败时删除评论，游客可以重新提交
+		if err := commentService.sendGuestVerification(comment); err != nil {
+			global.Log.Error("Failed to send guest comment verification email", zap.Uint("comment_id", comment.ID), zap.Error(err))
+			if err := global.DB.Delete(&comment).Error; err != nil {
+				global.Log.Error("Failed to delete unverified guest comment", zap.Uint("comment_id", comment.ID), zap.Error(err))
+			}
+			return 0, errors.New("failed to send the verification email, please try again later")
+		}
+	}
+	return status, nil
+}
+
+func (commentService *CommentService) CommentGuestVerify(token string) (appTypes.CommentStatus, error) {
+	key := guestCommentVerifyKey(token)
+	commentID, err := global.Redis.Get(key).Uint64()
+	if err != nil {
+		return 0, errors.New("the verification link is invalid or has expired")
+	}
+	global.Redis.Del(key)
+
+	var comment database.Comment
+	if err := global.DB.Take(&comment, commentID).Error; err != nil {
+		return 0, err
+	}
+	if comment.Status != appTypes.Unverified {
+		return comment.Status, nil
+	}
+
+	status := appTypes.Published
+	if global.Config.Comment.GuestModeration {
+		status = appTypes.Pending
+	}
+	if err := global.DB.Model(&comment).Update("guest_verified", true).Error; err != nil {
+		return 0, err
+	}
+	return status, commentService.changeStatus(global.DB, comment, status)
+}
+
+func (commentService *CommentService) CommentReview(req request.CommentReview) error {
+	status := appTypes.Rejected
+	if req.Approve {
+		status = appTypes.Published
+	}
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		for _, id := range req.IDs {
+			var comment database.Comment
+			if err := tx.Take(&comment, id).Error; err != nil {
+				return err
+			}
+			if err := commentService.changeStatus(tx, comment, status); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (CommentService *CommentService) CommentDelete(c *gin.Context, req request.CommentDelete) error {
 	if len(req.IDs) == 0 {
 		return nil
@@ -70,9 +154,7 @@ func (CommentService *CommentService) CommentDelete(c *gin.Context, req request.
 				return err
 			}
 			userUUID := utils.GetUUID(c)
-			userRoleID := utils.GetRoleID(c)
-
-			if userUUID != comment.UserUUID && userRoleID != appTypes.Admin {
+			if (comment.UserUUID == nil || userUUID != *comment.UserUUID) && !ServiceGroupApp.RBACService.HasPermission(utils.GetUserID(c), appTypes.PermissionCommentManage) {
 				return errors.New("you do not have permission to delete this comment")
 			}
 
@@ -122,6 +204,10 @@ func (commentService *CommentService) CommentList(info request.CommentList) ([]d
 		db = db.Where("content LIKE ?", "%"+*info.Content+"%")
 	}
 
+	if info.Status != nil {
+		db = db.Where("status = ?", appTypes.ToCommentStatus(*info.Status))
+	}
+
 	option := other.MySQLOption{
 		PageInfo: info.PageInfo,
 		Where:    db,
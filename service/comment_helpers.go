@@ -1,9 +1,18 @@
 package service
 
 import (
+	"encoding/json"
+	"html"
+	"net/url"
+	"server/eventbus"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/database"
+	"server/utils"
+	"strconv"
+	"time"
 
+	"github.com/gofrs/uuid"
 	"gorm.io/gorm"
 )
 
@@ -11,7 +20,7 @@ import (
 func (commentService *CommentService) LoadChildren(comment *database.Comment) error {
 	var children []database.Comment
 	// 查找子评论
-	if err := global.DB.Where("p_id = ?", comment.ID).Preload("User", func(db *gorm.DB) *gorm.DB {
+	if err := global.DB.Where("p_id = ? AND status = ?", comment.ID, appTypes.Published).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("uuid, username, avatar, address, signature")
 	}).Find(&children).Error; err != nil {
 		return err
@@ -42,8 +51,12 @@ func (commentService *CommentService) DeleteCommentAndChildren(tx *gorm.DB, comm
 			return err
 		}
 	}
-	return tx.Delete(&database.Comment{}, commentID).Error
-
+	// 加载完整的评论再删除，BeforeDelete 钩子需要根据文章 ID 和状态更新评论量
+	var comment database.Comment
+	if err := tx.Take(&comment, commentID).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&comment).Error
 }
 
 // 过滤一条评论的子评论
@@ -53,7 +66,7 @@ func (commentService *CommentService) FilterChildren(comments []database.Comment
 		var findChildren func([]database.Comment)
 		findChildren = func(children []database.Comment) {
 			for _, child := range children {
-				if child.UserUUID == comments[i].UserUUID {
+				if sameCommentUser(child.UserUUID, comments[i].UserUUID) {
 					mp[child.ID] = struct{}{}
 				}
 				if len(child.Children) > 0 {
@@ -66,3 +79,76 @@ func (commentService *CommentService) FilterChildren(comments []database.Comment
 	}
 	return mp
 }
+
+// sameCommentUser 判断两条评论是否由同一用户发表，游客评论之间视为同一用户
+func sameCommentUser(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// changeStatus 修改评论状态，评论由未发布变为已发布（或反之）时同步更新文章评论量
+func (commentService *CommentService) changeStatus(tx *gorm.DB, comment database.Comment, status appTypes.CommentStatus) error {
+	if comment.Status == status {
+		return nil
+	}
+	if err := tx.Model(&comment).Update("status", status).Error; err != nil {
+		return err
+	}
+
+	var delta int
+	if status == appTypes.Published {
+		delta = 1
+	} else if comment.Status == appTypes.Published {
+		delta = -1
+	}
+	if delta == 0 {
+		return nil
+	}
+	event := eventbus.ESEvent{
+		ArticleID: comment.ArticleID,
+		Field:     "comments",
+		Delta:     delta,
+	}
+	msgBytes, _ := json.Marshal(event)
+	return global.EventBus.Publish(eventbus.TopicESUpdate, msgBytes)
+}
+
+// guestCommentVerifyKey 游客评论验证令牌在 Redis 中的键，只保存令牌的哈希
+func guestCommentVerifyKey(token string) string {
+	return "guest_comment_verify:" + utils.SHA256Hex(token)
+}
+
+// sendGuestVerification 向游客邮箱发送评论验证链接，链接 24 小时内有效
+func (commentService *CommentService) sendGuestVerification(comment database.Comment) error {
+	token, err := utils.GenerateRandomKey(32)
+	if err != nil {
+		return err
+	}
+	if err := global.Redis.Set(guestCommentVerifyKey(token), strconv.FormatUint(uint64(comment.ID), 10), 24*time.Hour).Err(); err != nil {
+		return err
+	}
+
+	link := utils.APIURL("comment/guestVerify", url.Values{"token": {token}})
+	subject := "请验证您的评论"
+	// 昵称由游客填写，需要转义后再写入邮件
+	body := `亲爱的[` + html.EscapeString(comment.GuestNickname) + `]，<br/>
+<br/>
+感谢您在` + global.Config.Website.Name + `的个人博客发表评论！请点击以下链接验证您的邮箱，验证后评论才会显示：<br/>
+<br/>
+<a href="` + html.EscapeString(link) + `">` + html.EscapeString(link) + `</a><br/>
+该链接在 24 小时内有效。<br/>
+<br/>
+如果您没有发表过评论，请忽略此邮件。
+<br/>
+祝好，<br/>` +
+		global.Config.Website.Title + `<br/>
+<br/>`
+
+	if err := utils.Email(comment.GuestEmail, subject, body); err != nil {
+		global.Redis.Del(guestCommentVerifyKey(token))
+		return err
+	}
+	return nil
+}
@@ -4,7 +4,12 @@ type ServiceGroup struct {
 	EsService
 	BaseService
 	JwtService
+	JwtKeyService
+	SessionService
+	PersonalAccessTokenService
 	GaodeService
+	QQService
+	OAuthService
 	UserService
 	ImageService
 	ArticleService
@@ -12,10 +17,14 @@ type ServiceGroup struct {
 	AdvertisementService
 	FriendLinkService
 	FeedbackService
+	FollowService
 	WebsiteService
 	HotSearchService
 	CalendarService
 	ConfigService
+	RateLimitService
+	RBACService
+	QueueService
 }
 
 var ServiceGroupApp = new(ServiceGroup)
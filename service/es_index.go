@@ -22,6 +22,12 @@ func (esService *EsService) IndexDelete(indexName string) error {
 	return err
 }
 
+// IndexPutMapping 为已有的 Elasticsearch 索引添加字段映射
+func (esService *EsService) IndexPutMapping(indexName string, properties map[string]types.Property) error {
+	_, err := global.ESClient.Indices.PutMapping(indexName).Properties(properties).Do(context.TODO())
+	return err
+}
+
 // IndexExists 检查指定的 Elasticsearch 索引是否存在
 func (esService *EsService) IndexExists(indexName string) (bool, error) {
 	return global.ESClient.Indices.Exists(indexName).Do(context.TODO())
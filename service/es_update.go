@@ -0,0 +1,140 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"server/eventbus"
+	"server/global"
+	"server/model/elasticsearch"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/refresh"
+	"go.uber.org/zap"
+)
+
+// esUpdateFields 允许通过事件更新的文章字段，字段名会拼接进脚本，不能直接使用事件中的内容
+var esUpdateFields = map[string]bool{"views": true, "comments": true, "likes": true}
+
+// esItemError 批量更新中单篇文章的失败原因
+type esItemError struct {
+	reason    string
+	retryable bool
+}
+
+// HandleESUpdates 批量更新文章计数，成功的事件会被确认，失败的事件稍后重试，无法处理的事件进入死信
+func (articleService *ArticleService) HandleESUpdates(msgs []eventbus.Message) {
+	events := make([]eventbus.ESEvent, 0, len(msgs))
+	valid := make([]eventbus.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		var event eventbus.ESEvent
+		if err := json.Unmarshal(msg.Body(), &event); err != nil {
+			global.Log.Error("消息解析失败:", zap.Error(err))
+			msg.Fail("invalid message: "+err.Error(), false)
+			continue
+		}
+		if event.ArticleID == "" || !esUpdateFields[event.Field] {
+			msg.Fail(fmt.Sprintf("invalid event: article %q, field %q", event.ArticleID, event.Field), false)
+			continue
+		}
+		events = append(events, event)
+		valid = append(valid, msg)
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	failed, err := articleService.esUpdateBulk(events)
+	if err != nil {
+		global.Log.Error("批量更新失败:", zap.Error(err))
+		for _, msg := range valid {
+			msg.Fail(err.Error(), true)
+		}
+		return
+	}
+	for i, msg := range valid {
+		if itemErr, ok := failed[events[i].ArticleID]; ok {
+			msg.Fail(itemErr.reason, itemErr.retryable)
+			continue
+		}
+		msg.Ack()
+	}
+}
+
+// esUpdateBulk 按文章聚合后批量更新，返回更新失败的文章及原因
+func (articleService *ArticleService) esUpdateBulk(batch []eventbus.ESEvent) (map[string]esItemError, error) {
+	// 按文章ID和字段分组聚合
+	updates := make(map[string]map[string]int)
+	for _, event := range batch {
+		if _, ok := updates[event.ArticleID]; !ok {
+			updates[event.ArticleID] = make(map[string]int)
+		}
+		updates[event.ArticleID][event.Field] += event.Delta
+	}
+
+	// 构建批量请求体
+	var buf bytes.Buffer
+	for articleID, fields := range updates {
+		// --- 第一行：操作元数据 ---
+		meta := map[string]interface{}{
+			"update": map[string]interface{}{
+				"_index": elasticsearch.ArticleIndex(), // 替换为你的索引名
+				"_id":    articleID,
+			},
+		}
+		metaBytes, _ := json.Marshal(meta)
+		buf.Write(metaBytes)
+		buf.WriteByte('\n')
+
+		// --- 第二行：脚本和数据 ---
+		scriptParts := []string{}
+		params := make(map[string]int)
+		for field, delta := range fields {
+			scriptParts = append(scriptParts, fmt.Sprintf("ctx._source.%s += params.%s", field, field))
+			params[field] = delta
+		}
+
+		body := map[string]interface{}{
+			"script": map[string]interface{}{
+				"source": strings.Join(scriptParts, ";"),
+				"lang":   "painless",
+				"params": params,
+			},
+		}
+		bodyBytes, _ := json.Marshal(body)
+		buf.Write(bodyBytes)
+		buf.WriteByte('\n')
+	}
+
+	// 执行批量操作
+	res, err := global.ESClient.Bulk().Raw(bytes.NewReader(buf.Bytes())).Index(elasticsearch.ArticleIndex()).Refresh(refresh.True).Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	// 批量请求成功时仍可能有部分文章更新失败，限流和服务端错误可以重试，其他错误（例如文章已被删除）重试也不会成功
+	failed := make(map[string]esItemError)
+	if !res.Errors {
+		return failed, nil
+	}
+	for _, item := range res.Items {
+		for _, result := range item {
+			if result.Id_ == nil || result.Status < 300 {
+				continue
+			}
+			reason := fmt.Sprintf("status %d", result.Status)
+			if result.Error != nil {
+				reason += ": " + result.Error.Type
+				if result.Error.Reason != nil {
+					reason += ": " + *result.Error.Reason
+				}
+			}
+			failed[*result.Id_] = esItemError{
+				reason:    reason,
+				retryable: result.Status == 429 || result.Status >= 500,
+			}
+		}
+	}
+	return failed, nil
+}
@@ -23,7 +23,7 @@ func (feedbackService *FeedbackService) FeedbackNew() (feedbacks []database.Feed
 
 func (feedbackService *FeedbackService) FeedbackCreate(req request.FeedbackCreate) (err error) {
 	err = global.DB.Create(&database.Feedback{
-		UserUUID: req.UUID,
+		UserUUID: &req.UUID,
 		Content:  req.Content,
 	}).Error
 	if err != nil {
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/other"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+
+	"gorm.io/gorm"
+)
+
+// FollowService 提供关注用户、标签和类别相关的服务
+type FollowService struct {
+}
+
+// maxFeedFollows 生成关注动态时每种对象最多使用的关注数量，避免 Elasticsearch 查询过大
+const maxFeedFollows = 500
+
+// FollowCreate 关注用户、标签或类别
+func (followService *FollowService) FollowCreate(req request.Follow) error {
+	switch req.TargetType {
+	case appTypes.FollowUser:
+		var target database.User
+		if err := global.DB.Select("id").Where("uuid = ?", req.Target).First(&target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("the user does not exist")
+			}
+			return err
+		}
+		if target.ID == req.UserID {
+			return errors.New("you cannot follow yourself")
+		}
+	case appTypes.FollowTag:
+		if errors.Is(global.DB.Where("tag = ?", req.Target).First(&database.ArticleTag{}).Error, gorm.ErrRecordNotFound) {
+			return errors.New("the tag does not exist")
+		}
+	case appTypes.FollowCategory:
+		if errors.Is(global.DB.Where("category = ?", req.Target).First(&database.ArticleCategory{}).Error, gorm.ErrRecordNotFound) {
+			return errors.New("the category does not exist")
+		}
+	default:
+		return errors.New("invalid follow type")
+	}
+
+	follow := database.Follow{UserID: req.UserID, TargetType: req.TargetType, Target: req.Target}
+	return global.DB.Where(follow).FirstOrCreate(&follow).Error
+}
+
+// FollowDelete 取消关注
+func (followService *FollowService) FollowDelete(req request.Follow) error {
+	return global.DB.Where("user_id = ? AND target_type = ? AND target = ?", req.UserID, req.TargetType, req.Target).
+		Delete(&database.Follow{}).Error
+}
+
+// FollowIs 判断是否已关注
+func (followService *FollowService) FollowIs(req request.Follow) (bool, error) {
+	var count int64
+	err := global.DB.Model(&database.Follow{}).
+		Where("user_id = ? AND target_type = ? AND target = ?", req.UserID, req.TargetType, req.Target).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FollowList 获取我的关注列表，可以按对象类型筛选
+func (followService *FollowService) FollowList(info request.FollowList) (interface{}, int64, error) {
+	db := global.DB.Where("user_id = ?", info.UserID)
+	if info.TargetType != "" {
+		if !info.TargetType.IsValid() {
+			return nil, 0, errors.New("invalid follow type")
+		}
+		db = db.Where("target_type = ?", info.TargetType)
+	}
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+	}
+	return utils.MySQLPagination(&database.Follow{}, option)
+}
+
+// Followers 获取关注了该用户的用户列表
+func (followService *FollowService) Followers(info request.FollowUsers) (interface{}, int64, error) {
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    global.DB.Where("target_type = ? AND target = ?", appTypes.FollowUser, info.UUID),
+		Preload:  []string{"User"},
+	}
+	follows, total, err := utils.MySQLPagination(&database.Follow{}, option)
+	if err != nil {
+		return nil, 0, err
+	}
+	list := make([]response.UserCard, 0, len(follows))
+	for _, follow := range follows {
+		if follow.User.ID != 0 {
+			list = append(list, userCard(follow.User))
+		}
+	}
+	return list, total, nil
+}
+
+// Following 获取该用户关注的用户列表
+func (followService *FollowService) Following(info request.FollowUsers) (interface{}, int64, error) {
+	var user database.User
+	if err := global.DB.Select("id").Where("uuid = ?", info.UUID).First(&user).Error; err != nil {
+		return nil, 0, err
+	}
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    global.DB.Where("user_id = ? AND target_type = ?", user.ID, appTypes.FollowUser),
+	}
+	follows, total, err := utils.MySQLPagination(&database.Follow{}, option)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	uuids := make([]string, 0, len(follows))
+	for _, follow := range follows {
+		uuids = append(uuids, follow.Target)
+	}
+	var users []database.User
+	if len(uuids) > 0 {
+		if err := global.DB.Where("uuid IN ?", uuids).Find(&users).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+	// 按关注的顺序返回
+	usersByUUID := make(map[string]database.User, len(users))
+	for _, u := range users {
+		usersByUUID[u.UUID.String()] = u
+	}
+	list := make([]response.UserCard, 0, len(follows))
+	for _, follow := range follows {
+		if u, ok := usersByUUID[follow.Target]; ok {
+			list = append(list, userCard(u))
+		}
+	}
+	return list, total, nil
+}
+
+// FollowCounts 获取用户的粉丝数和关注的用户数
+func (followService *FollowService) FollowCounts(user database.User) (followers int64, following int64, err error) {
+	if err = global.DB.Model(&database.Follow{}).
+		Where("target_type = ? AND target = ?", appTypes.FollowUser, user.UUID.String()).
+		Count(&followers).Error; err != nil {
+		return
+	}
+	err = global.DB.Model(&database.Follow{}).
+		Where("user_id = ? AND target_type = ?", user.ID, appTypes.FollowUser).
+		Count(&following).Error
+	return
+}
+
+// feedTargets 获取用户关注的作者、标签和类别
+func (followService *FollowService) feedTargets(userID uint) (authors, tags, categories []string, err error) {
+	var follows []database.Follow
+	if err = global.DB.Where("user_id = ?", userID).Order("id desc").Find(&follows).Error; err != nil {
+		return
+	}
+	for _, follow := range follows {
+		switch follow.TargetType {
+		case appTypes.FollowUser:
+			if len(authors) < maxFeedFollows {
+				authors = append(authors, follow.Target)
+			}
+		case appTypes.FollowTag:
+			if len(tags) < maxFeedFollows {
+				tags = append(tags, follow.Target)
+			}
+		case appTypes.FollowCategory:
+			if len(categories) < maxFeedFollows {
+				categories = append(categories, follow.Target)
+			}
+		}
+	}
+	return
+}
+
+// userCard 用户卡片中公开的信息
+func userCard(user database.User) response.UserCard {
+	return response.UserCard{
+		UUID:      user.UUID,
+		Username:  user.Username,
+		Avatar:    user.Avatar,
+		Address:   user.Address,
+		Signature: user.Signature,
+	}
+}
@@ -99,3 +99,15 @@ func (gaodeService *GaodeService) GetWeatherByAdcode(adcode string) (other.Live,
 	// 返回当天的天气数据
 	return data.Lives[0], nil
 }
+
+// GetAddressByIP 获取IP地址对应的地理位置描述，获取失败时返回"未知"
+func (gaodeService *GaodeService) GetAddressByIP(ip string) string {
+	res, err := gaodeService.GetLocationByIP(ip)
+	if err != nil || res.Province == "" {
+		return "未知"
+	}
+	if res.City != "" && res.Province != res.City {
+		return res.Province + "-" + res.City
+	}
+	return res.Province
+}
@@ -1,63 +1,158 @@
 package service
 
 import (
-	"github.com/gofrs/uuid"
-	"go.uber.org/zap"
+	"encoding/json"
 	"server/global"
 	"server/model/database"
 	"server/utils"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
 )
 
 // JwtService 提供与JWT相关的服务
 type JwtService struct {
 }
 
-// SetRedisJWT 将JWT存储到Redis中
-func (jwtService *JwtService) SetRedisJWT(jwt string, uuid uuid.UUID) error {
-	// 解析配置中的JWT过期时间
-	dr, err := utils.ParseDuration(global.Config.Jwt.RefreshTokenExpiryTime)
-	if err != nil {
-		return err
-	}
-	// 设置JWT在Redis中的过期时间
-	return global.Redis.Set(uuid.String(), jwt, dr).Err()
-}
+// jwtBlacklistChannel 用于在多个实例之间广播黑名单的 Redis 频道
+const jwtBlacklistChannel = "jwt_blacklist"
 
-// GetRedisJWT 从Redis中获取JWT
-func (jwtService *JwtService) GetRedisJWT(uuid uuid.UUID) (string, error) {
-	// 从Redis获取指定uuid对应的JWT
-	return global.Redis.Get(uuid.String()).Result()
+// blacklistMessage 黑名单广播消息
+type blacklistMessage struct {
+	Hash      string    `json:"hash"`       // JWT 的 SHA-256 摘要
+	ExpiresAt time.Time `json:"expires_at"` // JWT 的过期时间
 }
 
 // JoinInBlacklist 将JWT添加到黑名单
 func (jwtService *JwtService) JoinInBlacklist(jwtList database.JwtBlacklist) error {
+	// 已过期的JWT本身就无法通过校验，无需加入黑名单
+	expiresAt := jwtExpiresAt(jwtList.Jwt)
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
 	// 将JWT记录插入到数据库中的黑名单表
+	jwtList.ExpiresAt = &expiresAt
 	if err := global.DB.Create(&jwtList).Error; err != nil {
 		return err
 	}
-	// 将JWT添加到内存中的黑名单缓存
-	global.BlackCache.SetDefault(jwtList.Jwt, struct{}{})
-	return nil
+
+	// 将JWT写入Redis，过期时间与JWT剩余的有效期一致
+	hash := utils.SHA256Hex(jwtList.Jwt)
+	if err := global.Redis.Set(jwtBlacklistKey(hash), 1, ttl).Err(); err != nil {
+		return err
+	}
+
+	// 将JWT添加到内存中的黑名单缓存，并通知其他实例
+	global.BlackCache.Set(hash, struct{}{}, ttl)
+	msg, _ := json.Marshal(blacklistMessage{Hash: hash, ExpiresAt: expiresAt})
+	return global.Redis.Publish(jwtBlacklistChannel, msg).Err()
 }
 
 // IsInBlacklist 检查JWT是否在黑名单中
 func (jwtService *JwtService) IsInBlacklist(jwt string) bool {
+	if jwt == "" {
+		return false
+	}
 	// 从黑名单缓存中检查JWT是否存在
-	_, ok := global.BlackCache.Get(jwt)
-	return ok
+	hash := utils.SHA256Hex(jwt)
+	if _, ok := global.BlackCache.Get(hash); ok {
+		return true
+	}
+
+	// 缓存可能错过了其他实例的广播，例如订阅断开期间加入的黑名单，再检查 Redis
+	ttl, err := global.Redis.PTTL(jwtBlacklistKey(hash)).Result()
+	if err != nil {
+		// Redis 不可用时只依赖本地缓存，避免所有请求都无法通过校验
+		global.Log.Error("Failed to check JWT blacklist in redis", zap.Error(err))
+		return false
+	}
+	if ttl <= 0 {
+		return false
+	}
+	global.BlackCache.Set(hash, struct{}{}, ttl)
+	return true
 }
 
-// LoadAll 从数据库加载所有的JWT黑名单并加入缓存
+// ClearExpiredBlacklist 删除已经过期的黑名单记录
+func (jwtService *JwtService) ClearExpiredBlacklist() error {
+	// 旧版本写入的记录没有过期时间，按照 Refresh Token 的最长有效期清理
+	refreshTokenExpiry, err := utils.ParseDuration(global.Config.Jwt.RefreshTokenExpiryTime)
+	if err != nil {
+		return err
+	}
+	return global.DB.Unscoped().
+		Where("expires_at < ?", time.Now()).
+		Or("expires_at IS NULL AND created_at < ?", time.Now().Add(-refreshTokenExpiry)).
+		Delete(&database.JwtBlacklist{}).Error
+}
+
+// LoadAll 从Redis加载所有未过期的JWT黑名单并加入缓存
 func LoadAll() {
+	var cursor uint64
+	for {
+		keys, next, err := global.Redis.Scan(cursor, jwtBlacklistKey("*"), 1000).Result()
+		if err != nil {
+			// 如果获取失败，记录错误日志
+			global.Log.Error("Failed to load JWT blacklist from redis", zap.Error(err))
+			return
+		}
+		// 将所有JWT添加到BlackCache缓存中，过期时间与Redis中的剩余时间一致
+		for _, key := range keys {
+			ttl, err := global.Redis.TTL(key).Result()
+			if err != nil || ttl <= 0 {
+				continue
+			}
+			global.BlackCache.Set(strings.TrimPrefix(key, jwtBlacklistKey("")), struct{}{}, ttl)
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	// 旧版本只写入了数据库，且没有记录过期时间，按照默认过期时间加入缓存
 	var data []string
-	// 从数据库中获取所有的黑名单JWT
-	if err := global.DB.Model(&database.JwtBlacklist{}).Pluck("jwt", &data).Error; err != nil {
-		// 如果获取失败，记录错误日志
+	if err := global.DB.Model(&database.JwtBlacklist{}).Where("expires_at IS NULL").Pluck("jwt", &data).Error; err != nil {
 		global.Log.Error("Failed to load JWT blacklist from the database", zap.Error(err))
 		return
 	}
-	// 将所有JWT添加到BlackCache缓存中
 	for i := 0; i < len(data); i++ {
-		global.BlackCache.SetDefault(data[i], struct{}{})
+		global.BlackCache.SetDefault(utils.SHA256Hex(data[i]), struct{}{})
 	}
 }
+
+// SubscribeBlacklist 订阅其他实例广播的黑名单，并写入本地缓存
+func SubscribeBlacklist() {
+	pubsub := global.Redis.Subscribe(jwtBlacklistChannel)
+	defer pubsub.Close()
+
+	for msg := range pubsub.Channel() {
+		var data blacklistMessage
+		if err := json.Unmarshal([]byte(msg.Payload), &data); err != nil {
+			global.Log.Error("Failed to parse JWT blacklist message", zap.Error(err))
+			continue
+		}
+		if ttl := time.Until(data.ExpiresAt); ttl > 0 {
+			global.BlackCache.Set(data.Hash, struct{}{}, ttl)
+		}
+	}
+}
+
+// jwtExpiresAt 获取JWT的过期时间，无法解析时按照 Refresh Token 的最长有效期计算
+func jwtExpiresAt(tokenString string) time.Time {
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, &claims); err == nil && claims.ExpiresAt != nil {
+		return claims.ExpiresAt.Time
+	}
+	refreshTokenExpiry, _ := utils.ParseDuration(global.Config.Jwt.RefreshTokenExpiryTime)
+	return time.Now().Add(refreshTokenExpiry)
+}
+
+// jwtBlacklistKey 黑名单中的JWT在 Redis 中的键
+func jwtBlacklistKey(hash string) string {
+	return "jwt_blacklist:" + hash
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"server/global"
+	"server/model/database"
+	"server/utils"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// JwtKeyService 提供 JWT 签名密钥的管理服务
+type JwtKeyService struct {
+}
+
+// jwtKeysChannel 用于在多个实例之间通知重新加载签名密钥的 Redis 频道
+const jwtKeysChannel = "jwt_keys"
+
+// ErrJwtKeyRotationInProgress 其他实例正在轮换密钥
+var ErrJwtKeyRotationInProgress = errors.New("jwt key rotation is already in progress, please try again later")
+
+// jwtKeyRotateLock 多个实例之间轮换密钥的锁
+const jwtKeyRotateLock = "jwt_keys:rotate_lock"
+
+// LoadJwtKeys 从数据库加载所有仍可用于校验的密钥，缺少签名密钥时生成新的密钥
+func (jwtKeyService *JwtKeyService) LoadJwtKeys() error {
+	var keys []database.JwtKey
+	if err := global.DB.Where("expires_at IS NULL OR expires_at > ?", time.Now()).Order("id").Find(&keys).Error; err != nil {
+		return err
+	}
+
+	for _, use := range []string{utils.JwtKeyUseAccess, utils.JwtKeyUseRefresh} {
+		if !hasSigningKey(keys, use) {
+			return jwtKeyService.RotateJwtKeys()
+		}
+	}
+	return utils.SetJwtKeys(keys)
+}
+
+// RotateJwtKeysIfDue 签名密钥超过轮换周期或签名算法发生变化时轮换密钥
+func (jwtKeyService *JwtKeyService) RotateJwtKeysIfDue() error {
+	if due, err := jwtKeyRotationDue(); err != nil || !due {
+		return err
+	}
+
+	// 多个实例同时检查时只由一个实例轮换
+	ok, err := global.Redis.SetNX(jwtKeyRotateLock, 1, time.Minute).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrJwtKeyRotationInProgress
+	}
+	defer global.Redis.Del(jwtKeyRotateLock)
+
+	// 获得锁之前其他实例可能已经完成了轮换
+	if due, err := jwtKeyRotationDue(); err != nil || !due {
+		return err
+	}
+	return jwtKeyService.RotateJwtKeys()
+}
+
+// jwtKeyRotationDue 判断签名密钥是否超过轮换周期、签名算法是否发生变化，或者缺少签名密钥
+func jwtKeyRotationDue() (bool, error) {
+	var keys []database.JwtKey
+	if err := global.DB.Where("retired_at IS NULL").Find(&keys).Error; err != nil {
+		return false, err
+	}
+
+	interval, _ := utils.ParseDuration(global.Config.Jwt.KeyRotationInterval)
+	for _, key := range keys {
+		if key.Algorithm != jwtAlgorithm() || (interval > 0 && time.Since(key.CreatedAt) >= interval) {
+			return true, nil
+		}
+	}
+	return !hasSigningKey(keys, utils.JwtKeyUseAccess) || !hasSigningKey(keys, utils.JwtKeyUseRefresh), nil
+}
+
+// RotateJwtKeys 为每种用途生成新的签名密钥，旧密钥停止签发，并在令牌的有效期内继续用于校验
+func (jwtKeyService *JwtKeyService) RotateJwtKeys() error {
+	accessExpiry, err := utils.ParseDuration(global.Config.Jwt.AccessTokenExpiryTime)
+	if err != nil {
+		return err
+	}
+	refreshExpiry, err := utils.ParseDuration(global.Config.Jwt.RefreshTokenExpiryTime)
+	if err != nil {
+		return err
+	}
+	grace := map[string]time.Duration{
+		utils.JwtKeyUseAccess:  accessExpiry,
+		utils.JwtKeyUseRefresh: refreshExpiry,
+	}
+
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for use, d := range grace {
+			key, err := utils.GenerateJwtKey(use, jwtAlgorithm())
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&database.JwtKey{}).
+				Where("key_use = ? AND retired_at IS NULL", use).
+				Updates(map[string]interface{}{"retired_at": now, "expires_at": now.Add(d)}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&key).Error; err != nil {
+				return err
+			}
+		}
+		// 已停止签发的非对称密钥不再需要私钥，HS256 的密钥同时用于校验，需要保留到密钥被删除
+		return tx.Model(&database.JwtKey{}).Where("retired_at IS NOT NULL AND algorithm <> ?", "HS256").Update("private_key", "").Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := jwtKeyService.reload(); err != nil {
+		return err
+	}
+	// 通知其他实例重新加载密钥
+	return global.Redis.Publish(jwtKeysChannel, "reload").Err()
+}
+
+// ClearExpiredJwtKeys 删除已经过了校验宽限期的密钥
+func (jwtKeyService *JwtKeyService) ClearExpiredJwtKeys() error {
+	return global.DB.Unscoped().Where("expires_at < ?", time.Now()).Delete(&database.JwtKey{}).Error
+}
+
+// reload 从数据库重新加载密钥，不生成新的密钥
+func (jwtKeyService *JwtKeyService) reload() error {
+	var keys []database.JwtKey
+	if err := global.DB.Where("expires_at IS NULL OR expires_at > ?", time.Now()).Order("id").Find(&keys).Error; err != nil {
+		return err
+	}
+	return utils.SetJwtKeys(keys)
+}
+
+// SubscribeJwtKeys 订阅其他实例的密钥轮换通知，并重新加载密钥
+func SubscribeJwtKeys() {
+	pubsub := global.Redis.Subscribe(jwtKeysChannel)
+	defer pubsub.Close()
+
+	for range pubsub.Channel() {
+		if err := ServiceGroupApp.JwtKeyService.reload(); err != nil {
+			global.Log.Error("Failed to reload JWT keys", zap.Error(err))
+		}
+	}
+}
+
+// hasSigningKey 判断密钥中是否有指定用途的签名密钥
+func hasSigningKey(keys []database.JwtKey, use string) bool {
+	for _, key := range keys {
+		if key.Use == use && key.RetiredAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// jwtAlgorithm 配置的签名算法，未配置时使用 HS256
+func jwtAlgorithm() string {
+	switch global.Config.Jwt.Algorithm {
+	case "RS256", "EdDSA":
+		return global.Config.Jwt.Algorithm
+	default:
+		return "HS256"
+	}
+}
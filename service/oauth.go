@@ -0,0 +1,282 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"server/config"
+	"server/global"
+	"server/model/other"
+	"server/model/response"
+	"server/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// OAuthService 提供通用的 OAuth2 / OpenID Connect 第三方登录服务
+type OAuthService struct {
+}
+
+// oidcDiscoveryCache 缓存各提供方的 OIDC 发现文档，键为发现地址
+var oidcDiscoveryCache sync.Map
+
+// oauthHTTPClient 请求登录提供方使用的客户端，设置超时避免提供方无响应时登录请求一直阻塞
+var oauthHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// OAuthProviders 返回所有已启用的登录提供方
+func (oauthService *OAuthService) OAuthProviders() []response.OAuthProvider {
+	providers := []response.OAuthProvider{}
+	if !global.Config.OAuth.Enable {
+		return providers
+	}
+	for _, provider := range global.Config.OAuth.Providers {
+		providers = append(providers, response.OAuthProvider{
+			Name:        provider.Name,
+			DisplayName: provider.DisplayName,
+		})
+	}
+	return providers
+}
+
+// OAuthURL 生成跳转到登录提供方的授权地址，purpose 为 login 或 link，关联账号时需要传入当前用户 ID
+func (oauthService *OAuthService) OAuthURL(providerName, purpose string, userID uint) (string, error) {
+	provider, err := oauthService.provider(providerName)
+	if err != nil {
+		return "", err
+	}
+	authURL, _, _, err := oauthService.endpoints(provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := utils.GenerateRandomKey(32)
+	if err != nil {
+		return "", err
+	}
+	stateBytes, _ := json.Marshal(other.OAuthState{Provider: provider.Name, Purpose: purpose, UserID: userID})
+	if err := global.Redis.Set(oauthStateKey(state), stateBytes, 10*time.Minute).Err(); err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {provider.ClientID},
+		"redirect_uri":  {provider.RedirectURI},
+		"scope":         {strings.Join(provider.Scopes, " ")},
+		"state":         {state},
+	}
+	separator := "?"
+	if strings.Contains(authURL, "?") {
+		separator = "&"
+	}
+	return authURL + separator + query.Encode(), nil
+}
+
+// OAuthExchange 校验 state 并使用授权码换取用户信息
+func (oauthService *OAuthService) OAuthExchange(providerName, code, state string) (other.OAuthUserInfo, other.OAuthState, error) {
+	// state 只能使用一次
+	key := oauthStateKey(state)
+	stateBytes, err := global.Redis.Get(key).Bytes()
+	if err != nil {
+		return other.OAuthUserInfo{}, other.OAuthState{}, errors.New("the login state is invalid or has expired")
+	}
+	global.Redis.Del(key)
+
+	var oauthState other.OAuthState
+	if err := json.Unmarshal(stateBytes, &oauthState); err != nil {
+		return other.OAuthUserInfo{}, other.OAuthState{}, err
+	}
+	if oauthState.Provider != providerName {
+		return other.OAuthUserInfo{}, other.OAuthState{}, errors.New("the login state does not match the provider")
+	}
+
+	provider, err := oauthService.provider(providerName)
+	if err != nil {
+		return other.OAuthUserInfo{}, other.OAuthState{}, err
+	}
+	_, tokenURL, userInfoURL, err := oauthService.endpoints(provider)
+	if err != nil {
+		return other.OAuthUserInfo{}, other.OAuthState{}, err
+	}
+
+	accessToken, err := oauthService.exchangeCode(provider, tokenURL, code)
+	if err != nil {
+		return other.OAuthUserInfo{}, other.OAuthState{}, err
+	}
+	userInfo, err := oauthService.fetchUserInfo(provider, userInfoURL, accessToken)
+	if err != nil {
+		return other.OAuthUserInfo{}, other.OAuthState{}, err
+	}
+	return userInfo, oauthState, nil
+}
+
+// provider 查找已启用的登录提供方
+func (oauthService *OAuthService) provider(name string) (config.OAuthProvider, error) {
+	if !global.Config.OAuth.Enable {
+		return config.OAuthProvider{}, errors.New("third-party login is not enabled")
+	}
+	provider, ok := global.Config.OAuth.Provider(name)
+	if !ok {
+		return config.OAuthProvider{}, fmt.Errorf("unknown login provider: %s", name)
+	}
+	return provider, nil
+}
+
+// endpoints 返回提供方的授权、令牌和用户信息地址，优先使用配置文件中的地址，其次使用 OIDC 发现文档
+func (oauthService *OAuthService) endpoints(provider config.OAuthProvider) (authURL, tokenURL, userInfoURL string, err error) {
+	authURL, tokenURL, userInfoURL = provider.AuthURL, provider.TokenURL, provider.UserInfoURL
+	if provider.Type == "github" {
+		if authURL == "" {
+			authURL = "https://github.com/login/oauth/authorize"
+		}
+		if tokenURL == "" {
+			tokenURL = "https://github.com/login/oauth/access_token"
+		}
+		if userInfoURL == "" {
+			userInfoURL = "https://api.github.com/user"
+		}
+	}
+
+	if provider.DiscoveryURL != "" && (authURL == "" || tokenURL == "" || userInfoURL == "") {
+		discovery, err := oauthService.discover(provider.DiscoveryURL)
+		if err != nil {
+			return "", "", "", err
+		}
+		if authURL == "" {
+			authURL = discovery.AuthorizationEndpoint
+		}
+		if tokenURL == "" {
+			tokenURL = discovery.TokenEndpoint
+		}
+		if userInfoURL == "" {
+			userInfoURL = discovery.UserinfoEndpoint
+		}
+	}
+
+	if authURL == "" || tokenURL == "" || userInfoURL == "" {
+		return "", "", "", fmt.Errorf("the endpoints of login provider %s are not configured", provider.Name)
+	}
+	return authURL, tokenURL, userInfoURL, nil
+}
+
+// discover 获取并缓存 OIDC 发现文档
+func (oauthService *OAuthService) discover(discoveryURL string) (other.OIDCDiscovery, error) {
+	if cached, ok := oidcDiscoveryCache.Load(discoveryURL); ok {
+		return cached.(other.OIDCDiscovery), nil
+	}
+
+	var discovery other.OIDCDiscovery
+	body, err := oauthService.do(http.MethodGet, discoveryURL, nil, nil)
+	if err != nil {
+		return discovery, err
+	}
+	if err := json.Unmarshal(body, &discovery); err != nil {
+		return discovery, err
+	}
+	oidcDiscoveryCache.Store(discoveryURL, discovery)
+	return discovery, nil
+}
+
+// exchangeCode 使用授权码换取 Access Token
+func (oauthService *OAuthService) exchangeCode(provider config.OAuthProvider, tokenURL, code string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {provider.RedirectURI},
+		"client_id":     {provider.ClientID},
+		"client_secret": {provider.ClientSecret},
+	}
+	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+	body, err := oauthService.do(http.MethodPost, tokenURL, headers, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	accessToken := gjson.GetBytes(body, "access_token").String()
+	if accessToken == "" {
+		return "", fmt.Errorf("failed to get access token: %s %s", gjson.GetBytes(body, "error").String(), gjson.GetBytes(body, "error_description").String())
+	}
+	return accessToken, nil
+}
+
+// fetchUserInfo 使用 Access Token 获取用户信息，并按照配置的字段映射提取需要的信息
+func (oauthService *OAuthService) fetchUserInfo(provider config.OAuthProvider, userInfoURL, accessToken string) (other.OAuthUserInfo, error) {
+	headers := map[string]string{"Authorization": "Bearer " + accessToken}
+	body, err := oauthService.do(http.MethodGet, userInfoURL, headers, nil)
+	if err != nil {
+		return other.OAuthUserInfo{}, err
+	}
+
+	subjectField, emailField, nameField, avatarField := provider.Fields()
+	userInfo := other.OAuthUserInfo{
+		Provider: provider.Name,
+		Subject:  gjson.GetBytes(body, subjectField).String(),
+		Email:    gjson.GetBytes(body, emailField).String(),
+		Name:     gjson.GetBytes(body, nameField).String(),
+		Avatar:   gjson.GetBytes(body, avatarField).String(),
+	}
+	if userInfo.Subject == "" {
+		return other.OAuthUserInfo{}, fmt.Errorf("the user info of login provider %s has no %s field", provider.Name, subjectField)
+	}
+
+	// GitHub 只允许公开已验证的邮箱，OIDC 提供方通过 email_verified 声明邮箱是否已验证
+	if provider.Type == "github" {
+		userInfo.EmailVerified = userInfo.Email != ""
+	} else {
+		userInfo.EmailVerified = gjson.GetBytes(body, "email_verified").Bool()
+	}
+
+	// GitHub 用户隐藏邮箱时，需要单独获取已验证的主邮箱
+	if provider.Type == "github" && userInfo.Email == "" {
+		emails, err := oauthService.do(http.MethodGet, strings.TrimSuffix(userInfoURL, "/user")+"/user/emails", headers, nil)
+		if err == nil {
+			gjson.ParseBytes(emails).ForEach(func(_, email gjson.Result) bool {
+				if email.Get("primary").Bool() && email.Get("verified").Bool() {
+					userInfo.Email = email.Get("email").String()
+					userInfo.EmailVerified = true
+					return false
+				}
+				return true
+			})
+		}
+	}
+	return userInfo, nil
+}
+
+// do 发送请求并返回响应体，响应状态码不是 200 时返回错误
+func (oauthService *OAuthService) do(method, urlStr string, headers map[string]string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, urlStr, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := oauthHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	byteData, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status code: %d", res.StatusCode)
+	}
+	return byteData, nil
+}
+
+// oauthStateKey 第三方登录 state 在 Redis 中的键
+func oauthStateKey(state string) string {
+	return "oauth_state:" + state
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/request"
+	"server/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PersonalAccessTokenService 提供个人访问令牌相关的服务
+type PersonalAccessTokenService struct {
+}
+
+const (
+	personalAccessTokenPrefix = "gbp_"      // 个人访问令牌的前缀，便于在代码和日志中识别泄露的令牌
+	maxPersonalAccessTokens   = 20          // 每个用户最多拥有的个人访问令牌数量
+	lastUsedUpdateInterval    = time.Minute // 最近使用时间的更新间隔，避免每次请求都写数据库
+)
+
+var ErrInvalidPersonalAccessToken = errors.New("the personal access token is invalid or has expired")
+
+// IsPersonalAccessToken 判断令牌是否为个人访问令牌
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, personalAccessTokenPrefix)
+}
+
+// TokenCreate 创建个人访问令牌，令牌明文只在此时返回一次
+func (patService *PersonalAccessTokenService) TokenCreate(req request.PersonalAccessTokenCreate) (string, database.PersonalAccessToken, error) {
+	var pat database.PersonalAccessToken
+	scopes := uniqueStrings(req.Scopes)
+	for _, scope := range scopes {
+		if !appTypes.IsValidScope(scope) {
+			return "", pat, errors.New("unknown scope: " + scope)
+		}
+	}
+
+	var count int64
+	if err := global.DB.Model(&database.PersonalAccessToken{}).Where("user_id = ?", req.UserID).Count(&count).Error; err != nil {
+		return "", pat, err
+	}
+	if count >= maxPersonalAccessTokens {
+		return "", pat, errors.New("too many personal access tokens, please revoke unused ones first")
+	}
+
+	key, err := utils.GenerateRandomKey(30)
+	if err != nil {
+		return "", pat, err
+	}
+	token := personalAccessTokenPrefix + key
+	pat = database.PersonalAccessToken{
+		UserID:    req.UserID,
+		Name:      req.Name,
+		TokenHash: utils.SHA256Hex(token),
+		Prefix:    token[:len(personalAccessTokenPrefix)+6],
+		Scopes:    scopes,
+	}
+	if req.ExpiresIn > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresIn)
+		pat.ExpiresAt = &expiresAt
+	}
+	if err := global.DB.Create(&pat).Error; err != nil {
+		return "", pat, err
+	}
+	return token, pat, nil
+}
+
+// TokenList 获取用户的个人访问令牌
+func (patService *PersonalAccessTokenService) TokenList(userID uint) ([]database.PersonalAccessToken, error) {
+	var list []database.PersonalAccessToken
+	err := global.DB.Where("user_id = ?", userID).Order("id desc").Find(&list).Error
+	return list, err
+}
+
+// TokenRevoke 撤销用户的个人访问令牌
+func (patService *PersonalAccessTokenService) TokenRevoke(userID, id uint) error {
+	result := global.DB.Unscoped().Where("id = ? AND user_id = ?", id, userID).Delete(&database.PersonalAccessToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("the personal access token does not exist")
+	}
+	return nil
+}
+
+// TokenRevokeAll 撤销用户的所有个人访问令牌，用于账户可能已被盗用的情况
+func (patService *PersonalAccessTokenService) TokenRevokeAll(userID uint) error {
+	return global.DB.Unscoped().Where("user_id = ?", userID).Delete(&database.PersonalAccessToken{}).Error
+}
+
+// TokenAuth 校验个人访问令牌，返回令牌所属的用户和权限范围，并记录最近一次使用
+func (patService *PersonalAccessTokenService) TokenAuth(token, ip string) (database.User, []string, error) {
+	var user database.User
+	var pat database.PersonalAccessToken
+	err := global.DB.Where("token_hash = ?", utils.SHA256Hex(token)).First(&pat).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, nil, ErrInvalidPersonalAccessToken
+	}
+	if err != nil {
+		return user, nil, err
+	}
+	now := time.Now()
+	if pat.ExpiresAt != nil && pat.ExpiresAt.Before(now) {
+		return user, nil, ErrInvalidPersonalAccessToken
+	}
+
+	if err := global.DB.Select("id", "uuid", "role_id", "freeze").Take(&user, pat.UserID).Error; err != nil {
+		return user, nil, ErrInvalidPersonalAccessToken
+	}
+	if user.Freeze {
+		return user, nil, errors.New("the user is frozen")
+	}
+
+	if pat.LastUsedAt == nil || now.Sub(*pat.LastUsedAt) > lastUsedUpdateInterval || pat.LastUsedIP != ip {
+		global.DB.Model(&pat).Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip})
+	}
+	return user, pat.Scopes, nil
+}
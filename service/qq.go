@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"server/global"
+	"server/model/other"
+	"server/utils"
+	"time"
+)
+
+// QQService 提供与 QQ 登录相关的服务
+type QQService struct {
+}
+
+// QQLoginURL 生成一次性的 state 并返回 QQ 登录的授权地址
+func (qqService *QQService) QQLoginURL() (string, error) {
+	state, err := utils.GenerateRandomKey(32)
+	if err != nil {
+		return "", err
+	}
+	if err := global.Redis.Set(qqStateKey(state), 1, 10*time.Minute).Err(); err != nil {
+		return "", err
+	}
+	return global.Config.QQ.QQLoginURL(state), nil
+}
+
+// VerifyState 校验 QQ 回调中的 state，state 只能使用一次
+func (qqService *QQService) VerifyState(state string) error {
+	n, err := global.Redis.Del(qqStateKey(state)).Result()
+	if err != nil || n == 0 {
+		return errors.New("the login state is invalid or has expired")
+	}
+	return nil
+}
+
+// GetAccessTokenByCode 通过 Authorization Code 获取 Access Token
+func (qqService *QQService) GetAccessTokenByCode(code string) (other.AccessTokenResponse, error) {
+	data := other.AccessTokenResponse{}
+	qqCfg := global.Config.QQ
+	params := map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     qqCfg.AppID,
+		"client_secret": qqCfg.AppKey,
+		"code":          code,
+		"redirect_uri":  qqCfg.RedirectURI,
+		"fmt":           "json",
+	}
+	if err := qqService.get(qqCfg.BaseURL()+"/oauth2.0/token", params, &data); err != nil {
+		return data, err
+	}
+	if data.Error != 0 || data.AccessToken == "" {
+		return data, fmt.Errorf("failed to get access token: %d %s", data.Error, data.ErrorDescription)
+	}
+	return data, nil
+}
+
+// GetOpenIDByAccessToken 通过 Access Token 获取用户的 OpenID
+func (qqService *QQService) GetOpenIDByAccessToken(accessToken string) (other.OpenIDResponse, error) {
+	data := other.OpenIDResponse{}
+	params := map[string]string{
+		"access_token": accessToken,
+		"fmt":          "json",
+	}
+	if err := qqService.get(global.Config.QQ.BaseURL()+"/oauth2.0/me", params, &data); err != nil {
+		return data, err
+	}
+	if data.Error != 0 || data.OpenID == "" {
+		return data, fmt.Errorf("failed to get openid: %d %s", data.Error, data.ErrorDescription)
+	}
+	return data, nil
+}
+
+// GetUserInfoByAccessTokenAndOpenid 通过 Access Token 和 OpenID 获取用户的 QQ 资料
+func (qqService *QQService) GetUserInfoByAccessTokenAndOpenid(accessToken, openID string) (other.UserInfoResponse, error) {
+	data := other.UserInfoResponse{}
+	params := map[string]string{
+		"access_token":       accessToken,
+		"oauth_consumer_key": global.Config.QQ.AppID,
+		"openid":             openID,
+	}
+	if err := qqService.get(global.Config.QQ.BaseURL()+"/user/get_user_info", params, &data); err != nil {
+		return data, err
+	}
+	if data.Ret != 0 {
+		return data, fmt.Errorf("failed to get user info: %d %s", data.Ret, data.Msg)
+	}
+	return data, nil
+}
+
+// get 发送 GET 请求并将返回的 JSON 解析到 data 中
+func (qqService *QQService) get(urlStr string, params map[string]string, data any) error {
+	res, err := utils.HttpRequest(urlStr, "GET", nil, params, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed with status code: %d", res.StatusCode)
+	}
+
+	byteData, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(byteData, data)
+}
+
+// qqStateKey QQ 登录 state 在 Redis 中的键
+func qqStateKey(state string) string {
+	return "qq_state:" + state
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"server/config"
+	"server/global"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// newQQServer 启动模拟的 QQ 互联接口，并将 QQ.APIBaseURL 指向该服务
+func newQQServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	global.Log = zap.NewNop()
+	global.Config = &config.Config{QQ: config.QQ{
+		Enable:      true,
+		AppID:       "app-id",
+		AppKey:      "app-key",
+		RedirectURI: "https://example.com/callback",
+		APIBaseURL:  server.URL + "/",
+	}}
+}
+
+// writeJSON 返回 JSON 格式的响应
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestQQLoginExchange(t *testing.T) {
+	newQQServer(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/oauth2.0/token":
+			if q.Get("grant_type") != "authorization_code" || q.Get("client_id") != "app-id" || q.Get("client_secret") != "app-key" ||
+				q.Get("redirect_uri") != "https://example.com/callback" || q.Get("fmt") != "json" {
+				writeJSON(w, map[string]any{"error": 100000, "error_description": "invalid request"})
+				return
+			}
+			if q.Get("code") != "good-code" {
+				writeJSON(w, map[string]any{"error": 100019, "error_description": "code to access token error"})
+				return
+			}
+			writeJSON(w, map[string]any{"access_token": "access-token", "expires_in": "7776000", "refresh_token": "refresh-token"})
+		case "/oauth2.0/me":
+			if q.Get("access_token") != "access-token" {
+				writeJSON(w, map[string]any{"error": 100016, "error_description": "access token check failed"})
+				return
+			}
+			writeJSON(w, map[string]any{"client_id": "app-id", "openid": "open-id"})
+		case "/user/get_user_info":
+			if q.Get("access_token") != "access-token" || q.Get("oauth_consumer_key") != "app-id" || q.Get("openid") != "open-id" {
+				writeJSON(w, map[string]any{"ret": -1, "msg": "client request's parameters are invalid"})
+				return
+			}
+			writeJSON(w, map[string]any{"ret": 0, "nickname": "nickname", "figureurl_qq_2": "https://example.com/avatar.png"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	qqService := QQService{}
+	token, err := qqService.GetAccessTokenByCode("good-code")
+	if err != nil || token.AccessToken != "access-token" {
+		t.Fatalf("GetAccessTokenByCode() = (%+v, %v)", token, err)
+	}
+	openID, err := qqService.GetOpenIDByAccessToken(token.AccessToken)
+	if err != nil || openID.OpenID != "open-id" {
+		t.Fatalf("GetOpenIDByAccessToken() = (%+v, %v)", openID, err)
+	}
+	info, err := qqService.GetUserInfoByAccessTokenAndOpenid(token.AccessToken, openID.OpenID)
+	if err != nil || info.Nickname != "nickname" || info.FigureURLQQ2 != "https://example.com/avatar.png" {
+		t.Fatalf("GetUserInfoByAccessTokenAndOpenid() = (%+v, %v)", info, err)
+	}
+
+	if _, err := qqService.GetAccessTokenByCode("bad-code"); err == nil {
+		t.Errorf("GetAccessTokenByCode() accepted an invalid code")
+	}
+	if _, err := qqService.GetOpenIDByAccessToken("bad-token"); err == nil {
+		t.Errorf("GetOpenIDByAccessToken() accepted an invalid access token")
+	}
+	if _, err := qqService.GetUserInfoByAccessTokenAndOpenid(token.AccessToken, "other-open-id"); err == nil {
+		t.Errorf("GetUserInfoByAccessTokenAndOpenid() accepted an invalid openid")
+	}
+}
+
+func TestQQErrorResponses(t *testing.T) {
+	tests := []struct {
+		name            string
+		handler         http.HandlerFunc
+		wantUserInfoErr bool
+	}{
+		{"status code", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}, true},
+		{"invalid json", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("callback( {\"error\":100016} );"))
+		}, true},
+		{"empty response", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{})
+		}, false},
+		{"provider error", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{"error": 100013, "error_description": "access token is revoked", "ret": 100013, "msg": "access token is revoked"})
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newQQServer(t, tt.handler)
+			qqService := QQService{}
+			if _, err := qqService.GetAccessTokenByCode("code"); err == nil {
+				t.Errorf("GetAccessTokenByCode() error = nil")
+			}
+			if _, err := qqService.GetOpenIDByAccessToken("access-token"); err == nil {
+				t.Errorf("GetOpenIDByAccessToken() error = nil")
+			}
+			// get_user_info 只以 ret 表示错误，空响应视为成功
+			if _, err := qqService.GetUserInfoByAccessTokenAndOpenid("access-token", "open-id"); (err != nil) != tt.wantUserInfoErr {
+				t.Errorf("GetUserInfoByAccessTokenAndOpenid() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestQQLoginURL(t *testing.T) {
+	qq := config.QQ{AppID: "app-id", RedirectURI: "https://example.com/callback?a=b"}
+	want := "https://graph.qq.com/oauth2.0/authorize?response_type=code&client_id=app-id&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback%3Fa%3Db&state=s%2Bt"
+	if got := qq.QQLoginURL("s+t"); got != want {
+		t.Errorf("QQLoginURL() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"server/eventbus"
+	"server/global"
+	"server/model/request"
+	"server/rabbitmq"
+)
+
+// QueueService 提供消费者状态查询，以及消息队列死信的查看和重放
+type QueueService struct {
+}
+
+// deadLetterQueues 配置了重试和死信队列的队列
+var deadLetterQueues = []string{eventbus.TopicESUpdate}
+
+// ConsumerStatuses 获取所有消费者的运行状态
+func (queueService *QueueService) ConsumerStatuses() []rabbitmq.ConsumerStatus {
+	return rabbitmq.ConsumerStatuses()
+}
+
+// DeadLetterList 查看死信队列中最早的消息，不会移除消息
+func (queueService *QueueService) DeadLetterList(req request.DeadLetterList) ([]rabbitmq.DeadLetter, int, error) {
+	queue, err := deadLetterSource(req.Queue)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+	return rabbitmq.DeadLetters(queue, limit)
+}
+
+// DeadLetterReplay 把死信队列中最早的消息重新发布到原队列
+func (queueService *QueueService) DeadLetterReplay(req request.DeadLetterReplay) (int, error) {
+	queue, err := deadLetterSource(req.Queue)
+	if err != nil {
+		return 0, err
+	}
+	return rabbitmq.ReplayDeadLetters(queue, req.Limit)
+}
+
+// deadLetterSource 校验队列是否配置了死信队列，未指定时使用 ES 更新队列
+func deadLetterSource(queue string) (string, error) {
+	if global.Config.EventBus.Type == "memory" {
+		return "", errors.New("dead-letter queues are only available with the rabbitmq event bus")
+	}
+	if queue == "" {
+		return eventbus.TopicESUpdate, nil
+	}
+	for _, q := range deadLetterQueues {
+		if q == queue {
+			return queue, nil
+		}
+	}
+	return "", errors.New("the queue does not have a dead-letter queue")
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"fmt"
+	"server/global"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/gofrs/uuid"
+)
+
+// RateLimitService 提供基于 Redis 的滑动窗口限流
+type RateLimitService struct {
+}
+
+// slidingWindowScript 原子地清理窗口外的请求记录并尝试记录本次请求，
+// 返回 {是否允许, 距离窗口内最早一次请求过期还需等待的毫秒数}
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+if redis.call('ZCARD', key) < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return {1, 0}
+end
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// Allow 判断 key 在 window 时间内的请求次数是否超过 limit，超过时返回还需等待的时间
+func (rateLimitService *RateLimitService) Allow(key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	nowMs := time.Now().UnixNano() / int64(time.Millisecond)
+	// 每次请求使用唯一的成员，避免同一毫秒内的请求互相覆盖
+	member := uuid.Must(uuid.NewV4()).String()
+
+	res, err := slidingWindowScript.Run(&global.Redis, []string{"rate_limit:" + key}, nowMs, window.Milliseconds(), limit, member).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script result: %v", res)
+	}
+	allowed, _ := values[0].(int64)
+	retryAfter, _ := values[1].(int64)
+	return allowed == 1, time.Duration(retryAfter) * time.Millisecond, nil
+}
@@ -0,0 +1,479 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/request"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RBACService 提供角色和权限相关的服务
+type RBACService struct {
+}
+
+const (
+	userPermissionsPrefix = "user_permissions:" // 用户权限在 Redis 中的缓存键前缀
+	userPermissionsTTL    = 10 * time.Minute    // 用户权限在 Redis 中的缓存时间
+)
+
+// builtInRoles 内置角色及其初始权限，管理员角色始终拥有所有权限
+var builtInRoles = []struct {
+	Name        string
+	DisplayName string
+	Description string
+	Permissions []string
+}{
+	{appTypes.RoleAdmin, "管理员", "拥有所有权限", nil},
+	{appTypes.RoleEditor, "编辑", "管理文章和图片", []string{appTypes.PermissionArticleManage, appTypes.PermissionImageManage}},
+	{appTypes.RoleModerator, "审核员", "管理评论和反馈", []string{appTypes.PermissionCommentManage, appTypes.PermissionFeedbackManage}},
+	{appTypes.RoleUser, "普通用户", "没有管理权限", nil},
+}
+
+// InitRBAC 同步权限表，创建缺少的内置角色，并保证管理员角色拥有所有权限
+func (rbacService *RBACService) InitRBAC() error {
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		var all []database.Permission
+		for _, p := range appTypes.Permissions {
+			permission := database.Permission{Name: p.Name}
+			if err := tx.Where("name = ?", p.Name).Attrs(database.Permission{Description: p.Description}).FirstOrCreate(&permission).Error; err != nil {
+				return err
+			}
+			if permission.Description != p.Description {
+				if err := tx.Model(&permission).Update("description", p.Description).Error; err != nil {
+					return err
+				}
+			}
+			all = append(all, permission)
+		}
+
+		for _, r := range builtInRoles {
+			var role database.Role
+			err := tx.Where("name = ?", r.Name).First(&role).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				role = database.Role{Name: r.Name, DisplayName: r.DisplayName, Description: r.Description, BuiltIn: true}
+				if err := tx.Create(&role).Error; err != nil {
+					return err
+				}
+				var permissions []database.Permission
+				for _, permission := range all {
+					for _, name := range r.Permissions {
+						if permission.Name == name {
+							permissions = append(permissions, permission)
+						}
+					}
+				}
+				if len(permissions) > 0 {
+					if err := tx.Model(&role).Association("Permissions").Replace(permissions); err != nil {
+						return err
+					}
+				}
+			} else if err != nil {
+				return err
+			}
+
+			if r.Name == appTypes.RoleAdmin {
+				if err := tx.Model(&role).Association("Permissions").Replace(all); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	rbacService.clearPermissionsCache()
+	return nil
+}
+
+// UserPermissions 获取用户拥有的所有权限
+func (rbacService *RBACService) UserPermissions(userID uint) ([]string, error) {
+	key := userPermissionsKey(userID)
+	if data, err := global.Redis.Get(key).Bytes(); err == nil {
+		var permissions []string
+		if err := json.Unmarshal(data, &permissions); err == nil {
+			return permissions, nil
+		}
+	}
+
+	roles, err := rbacService.UserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	permissions := []string{}
+	seen := make(map[string]bool)
+	for _, role := range roles {
+		for _, permission := range role.Permissions {
+			if !seen[permission.Name] {
+				seen[permission.Name] = true
+				permissions = append(permissions, permission.Name)
+			}
+		}
+	}
+
+	if data, err := json.Marshal(permissions); err == nil {
+		global.Redis.Set(key, data, userPermissionsTTL)
+	}
+	return permissions, nil
+}
+
+// HasPermission 判断用户是否拥有指定的权限
+func (rbacService *RBACService) HasPermission(userID uint, permission string) bool {
+	permissions, err := rbacService.UserPermissions(userID)
+	if err != nil {
+		global.Log.Error("Failed to get user permissions:", zap.Error(err))
+		return false
+	}
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// UserRoles 获取用户的角色，没有分配角色的用户按 User.RoleID 使用对应的内置角色
+func (rbacService *RBACService) UserRoles(userID uint) ([]database.Role, error) {
+	var roles []database.Role
+	if err := global.DB.Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	if len(roles) > 0 {
+		return roles, nil
+	}
+
+	var user database.User
+	if err := global.DB.Select("id, role_id").Take(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	name := builtInRoleName(user.RoleID)
+	if name == "" {
+		return roles, nil
+	}
+	var role database.Role
+	err := global.DB.Preload("Permissions").Where("name = ?", name).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return roles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(roles, role), nil
+}
+
+// UserRolesUpdate 重新分配用户的角色，并同步 User.RoleID，操作者只能授予或收回自己拥有全部权限的角色
+func (rbacService *RBACService) UserRolesUpdate(operatorID uint, req request.UserRolesUpdate) error {
+	var user database.User
+	if err := global.DB.Take(&user, req.UserID).Error; err != nil {
+		return err
+	}
+
+	ids := make([]uint, 0, len(req.RoleIDs))
+	seen := make(map[uint]bool)
+	for _, id := range req.RoleIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	var roles []database.Role
+	if len(ids) > 0 {
+		if err := global.DB.Preload("Permissions").Where("id IN ?", ids).Find(&roles).Error; err != nil {
+			return err
+		}
+		if len(roles) != len(ids) {
+			return errors.New("role not found")
+		}
+	}
+	current, err := rbacService.UserRoles(user.ID)
+	if err != nil {
+		return err
+	}
+
+	// 新增和移除的角色都需要操作者拥有角色的全部权限，管理员角色只能由管理员分配或收回
+	changed := make(map[uint]database.Role)
+	currentIDs := make(map[uint]bool)
+	for _, role := range current {
+		currentIDs[role.ID] = true
+		if !seen[role.ID] {
+			changed[role.ID] = role
+		}
+	}
+	for _, role := range roles {
+		if !currentIDs[role.ID] {
+			changed[role.ID] = role
+		}
+	}
+	for _, role := range changed {
+		if role.Name == appTypes.RoleAdmin && !rbacService.isAdmin(operatorID) {
+			return errors.New("only administrators can grant or revoke the administrator role")
+		}
+		names := make([]string, 0, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			names = append(names, permission.Name)
+		}
+		if err := rbacService.checkGrantable(operatorID, names); err != nil {
+			return err
+		}
+	}
+
+	var adminRole database.Role
+	if err := global.DB.Where("name = ?", appTypes.RoleAdmin).First(&adminRole).Error; err != nil {
+		return err
+	}
+	grantAdmin := seen[adminRole.ID]
+	if !grantAdmin {
+		// 至少保留一个管理员，避免没有人能再分配角色
+		for _, role := range current {
+			if role.ID == adminRole.ID && !rbacService.hasOtherAdmin(user.ID, adminRole.ID) {
+				return errors.New("at least one administrator is required")
+			}
+		}
+	}
+
+	roleID := appTypes.User
+	if grantAdmin {
+		roleID = appTypes.Admin
+	}
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.ID).Delete(&database.UserRole{}).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := tx.Create(&database.UserRole{UserID: user.ID, RoleID: id}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&user).Update("role_id", roleID).Error
+	})
+	if err != nil {
+		return err
+	}
+	global.Redis.Del(userPermissionsKey(user.ID))
+	return nil
+}
+
+// RoleList 获取所有角色及其权限
+func (rbacService *RBACService) RoleList() ([]database.Role, error) {
+	var roles []database.Role
+	err := global.DB.Preload("Permissions").Order("id").Find(&roles).Error
+	return roles, err
+}
+
+// PermissionList 获取所有权限
+func (rbacService *RBACService) PermissionList() ([]database.Permission, error) {
+	var permissions []database.Permission
+	err := global.DB.Order("id").Find(&permissions).Error
+	return permissions, err
+}
+
+// RoleCreate 创建角色，操作者只能使用自己拥有的权限
+func (rbacService *RBACService) RoleCreate(operatorID uint, req request.RoleCreate) error {
+	if !errors.Is(global.DB.Where("name = ?", req.Name).First(&database.Role{}).Error, gorm.ErrRecordNotFound) {
+		return errors.New("the role already exists")
+	}
+	permissions, err := rbacService.findPermissions(req.Permissions)
+	if err != nil {
+		return err
+	}
+	if err := rbacService.checkGrantable(operatorID, req.Permissions); err != nil {
+		return err
+	}
+	role := database.Role{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+	return global.DB.Create(&role).Error
+}
+
+// RoleUpdate 更新角色的信息和权限，管理员角色的权限不能修改，操作者只能增加或移除自己拥有的权限
+func (rbacService *RBACService) RoleUpdate(operatorID uint, req request.RoleUpdate) error {
+	var role database.Role
+	if err := global.DB.Preload("Permissions").Take(&role, req.ID).Error; err != nil {
+		return err
+	}
+	permissions, err := rbacService.findPermissions(req.Permissions)
+	if err != nil {
+		return err
+	}
+	if role.Name != appTypes.RoleAdmin {
+		before := make(map[string]bool)
+		for _, permission := range role.Permissions {
+			before[permission.Name] = true
+		}
+		after := make(map[string]bool)
+		var diff []string
+		for _, permission := range permissions {
+			after[permission.Name] = true
+			if !before[permission.Name] {
+				diff = append(diff, permission.Name)
+			}
+		}
+		for name := range before {
+			if !after[name] {
+				diff = append(diff, name)
+			}
+		}
+		if err := rbacService.checkGrantable(operatorID, diff); err != nil {
+			return err
+		}
+	}
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&role).Updates(map[string]interface{}{"display_name": req.DisplayName, "description": req.Description}).Error; err != nil {
+			return err
+		}
+		if role.Name == appTypes.RoleAdmin {
+			return nil
+		}
+		return tx.Model(&role).Association("Permissions").Replace(permissions)
+	})
+	if err != nil {
+		return err
+	}
+	rbacService.clearPermissionsCache()
+	return nil
+}
+
+// RoleDelete 删除角色，内置角色不能删除
+func (rbacService *RBACService) RoleDelete(id uint) error {
+	var role database.Role
+	if err := global.DB.Take(&role, id).Error; err != nil {
+		return err
+	}
+	if role.BuiltIn {
+		return errors.New("built-in roles cannot be deleted")
+	}
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", role.ID).Delete(&database.UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&role).Error
+	})
+	if err != nil {
+		return err
+	}
+	rbacService.clearPermissionsCache()
+	return nil
+}
+
+// findPermissions 根据名称查找权限，存在未知的权限时返回错误
+func (rbacService *RBACService) findPermissions(names []string) ([]database.Permission, error) {
+	var permissions []database.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	if err := global.DB.Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(uniqueStrings(names)) {
+		return nil, errors.New("permission not found")
+	}
+	return permissions, nil
+}
+
+// checkGrantable 校验操作者拥有所有要授予或收回的权限，避免持有角色管理权限的用户获得或分配更高的权限
+func (rbacService *RBACService) checkGrantable(operatorID uint, permissions []string) error {
+	held, err := rbacService.UserPermissions(operatorID)
+	if err != nil {
+		return err
+	}
+	holds := make(map[string]bool, len(held))
+	for _, p := range held {
+		holds[p] = true
+	}
+	for _, p := range permissions {
+		if !holds[p] {
+			return errors.New("you cannot grant or revoke the " + p + " permission that you do not have")
+		}
+	}
+	return nil
+}
+
+// isAdmin 判断用户是否拥有管理员角色
+func (rbacService *RBACService) isAdmin(userID uint) bool {
+	roles, err := rbacService.UserRoles(userID)
+	if err != nil {
+		global.Log.Error("Failed to get user roles:", zap.Error(err))
+		return false
+	}
+	for _, role := range roles {
+		if role.Name == appTypes.RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOtherAdmin 判断除指定用户外是否还有其他管理员
+func (rbacService *RBACService) hasOtherAdmin(userID, adminRoleID uint) bool {
+	var count int64
+	err := global.DB.Model(&database.User{}).
+		Where("id <> ?", userID).
+		Where("(EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id AND user_roles.role_id = ?) OR (role_id = ? AND NOT EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id)))", adminRoleID, appTypes.Admin).
+		Count(&count).Error
+	return err == nil && count > 0
+}
+
+// clearPermissionsCache 清除所有用户的权限缓存，在角色的权限变化时调用
+func (rbacService *RBACService) clearPermissionsCache() {
+	var cursor uint64
+	for {
+		keys, next, err := global.Redis.Scan(cursor, userPermissionsPrefix+"*", 1000).Result()
+		if err != nil {
+			global.Log.Error("Failed to clear permissions cache:", zap.Error(err))
+			return
+		}
+		if len(keys) > 0 {
+			global.Redis.Del(keys...)
+		}
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
+
+// builtInRoleName 没有分配角色的用户按 User.RoleID 对应的内置角色
+func builtInRoleName(roleID appTypes.RoleID) string {
+	switch roleID {
+	case appTypes.Admin:
+		return appTypes.RoleAdmin
+	case appTypes.User:
+		return appTypes.RoleUser
+	default:
+		return ""
+	}
+}
+
+// uniqueStrings 去除重复的字符串
+func uniqueStrings(items []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// userPermissionsKey 用户权限在 Redis 中的缓存键
+func userPermissionsKey(userID uint) string {
+	return userPermissionsPrefix + strconv.FormatUint(uint64(userID), 10)
+}
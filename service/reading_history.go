@@ -0,0 +1,299 @@
+package service
+
+import (
+	"encoding/json"
+	"server/global"
+	"server/model/database"
+	"server/model/other"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+// 阅读上报先写入 Redis 哈希，由定时任务批量写入 MySQL，字段为 "<用户 ID>:<文章 ID>"
+const (
+	readingHistoryBufferKey   = "reading_history_buffer"
+	readingHistoryFlushingKey = "reading_history_buffer:flushing"
+)
+
+// readingBeacon 缓冲在 Redis 中的一次阅读上报
+type readingBeacon struct {
+	Progress float64   `json:"progress"`
+	Position string    `json:"position"`
+	ReadAt   time.Time `json:"read_at"`
+}
+
+// ReadingBeacon 记录阅读上报，只写入 Redis
+func (articleService *ArticleService) ReadingBeacon(req request.ReadingBeacon) error {
+	value, err := json.Marshal(readingBeacon{Progress: req.Progress, Position: req.Position, ReadAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	return global.Redis.HSet(readingHistoryBufferKey, readingHistoryField(req.UserID, req.ArticleID), value).Err()
+}
+
+// ReadingHistoryList 获取阅读记录，按最后阅读时间倒序
+func (articleService *ArticleService) ReadingHistoryList(info request.ReadingHistoryList) (interface{}, int64, error) {
+	// 先写入该用户还在缓冲中的记录，保证刚读过的文章出现在列表中
+	if err := articleService.flushUserReadingHistory(info.UserID); err != nil {
+		return nil, 0, err
+	}
+
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    global.DB.Where("user_id = ?", info.UserID),
+		Order:    "read_at desc",
+	}
+	l, total, err := utils.MySQLPagination(&database.ReadingHistory{}, option)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]string, 0, len(l))
+	for _, v := range l {
+		ids = append(ids, v.ArticleID)
+	}
+	articles, err := articleService.MGet(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	list := make([]response.ReadingHistory, 0, len(l))
+	for _, v := range l {
+		article := articles[v.ArticleID]
+		list = append(list, response.ReadingHistory{
+			ArticleID: v.ArticleID,
+			Title:     article.Title,
+			Cover:     article.Cover,
+			Progress:  v.Progress,
+			Position:  v.Position,
+			ReadAt:    v.ReadAt,
+		})
+	}
+	return list, total, nil
+}
+
+// ReadingHistoryClear 删除指定文章的阅读记录，未指定文章时清空全部
+func (articleService *ArticleService) ReadingHistoryClear(req request.ReadingHistoryClear) error {
+	if len(req.ArticleIDs) == 0 {
+		if err := articleService.clearReadingHistoryBuffer(req.UserID); err != nil {
+			return err
+		}
+		return global.DB.Where("user_id = ?", req.UserID).Delete(&database.ReadingHistory{}).Error
+	}
+
+	fields := make([]string, 0, len(req.ArticleIDs))
+	for _, articleID := range req.ArticleIDs {
+		fields = append(fields, readingHistoryField(req.UserID, articleID))
+	}
+	for _, key := range []string{readingHistoryBufferKey, readingHistoryFlushingKey} {
+		if err := global.Redis.HDel(key, fields...).Err(); err != nil {
+			return err
+		}
+	}
+	return global.DB.Where("user_id = ? AND article_id IN ?", req.UserID, req.ArticleIDs).Delete(&database.ReadingHistory{}).Error
+}
+
+// ReadingResume 获取文章上次阅读的位置，没有阅读过时返回 nil
+func (articleService *ArticleService) ReadingResume(req request.ReadingResume) (*response.ReadingHistory, error) {
+	field := readingHistoryField(req.UserID, req.ArticleID)
+	for _, key := range []string{readingHistoryBufferKey, readingHistoryFlushingKey} {
+		value, err := global.Redis.HGet(key, field).Result()
+		if err != nil {
+			continue
+		}
+		var beacon readingBeacon
+		if err := json.Unmarshal([]byte(value), &beacon); err != nil {
+			continue
+		}
+		return &response.ReadingHistory{
+			ArticleID: req.ArticleID,
+			Progress:  beacon.Progress,
+			Position:  beacon.Position,
+			ReadAt:    beacon.ReadAt,
+		}, nil
+	}
+
+	var history []database.ReadingHistory
+	if err := global.DB.Where("user_id = ? AND article_id = ?", req.UserID, req.ArticleID).Limit(1).Find(&history).Error; err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+	return &response.ReadingHistory{
+		ArticleID: history[0].ArticleID,
+		Progress:  history[0].Progress,
+		Position:  history[0].Position,
+		ReadAt:    history[0].ReadAt,
+	}, nil
+}
+
+// FlushReadingHistory 把 Redis 中缓冲的阅读上报写入 MySQL
+func (articleService *ArticleService) FlushReadingHistory() error {
+	// 上次写入失败时留下的数据先处理，全部写入后再把缓冲区整体改名，新的上报会写入新的缓冲区
+	exists, err := global.Redis.Exists(readingHistoryFlushingKey).Result()
+	if err != nil {
+		return err
+	}
+	if exists > 0 {
+		if err := flushReadingHistoryKey(readingHistoryFlushingKey); err != nil {
+			return err
+		}
+		if exists, err := global.Redis.Exists(readingHistoryFlushingKey).Result(); err != nil || exists > 0 {
+			return err
+		}
+	}
+
+	if err := global.Redis.Rename(readingHistoryBufferKey, readingHistoryFlushingKey).Err(); err != nil {
+		// 缓冲区不存在说明没有新的上报
+		if strings.Contains(err.Error(), "no such key") {
+			return nil
+		}
+		return err
+	}
+	return flushReadingHistoryKey(readingHistoryFlushingKey)
+}
+
+// flushReadingHistoryKey 把哈希中的阅读上报写入 MySQL，只删除写入成功的字段，失败的字段留到下次重试
+func flushReadingHistoryKey(key string) error {
+	entries, err := global.Redis.HGetAll(key).Result()
+	if err != nil {
+		return err
+	}
+	saved := make([]string, 0, len(entries))
+	for field, value := range entries {
+		if err := saveReadingHistory(field, value); err != nil {
+			global.Log.Error("Failed to save reading history", zap.String("field", field), zap.Error(err))
+			continue
+		}
+		saved = append(saved, field)
+	}
+	if len(saved) == 0 {
+		return nil
+	}
+	return global.Redis.HDel(key, saved...).Err()
+}
+
+// hdelIfUnchangedScript 字段的值没有变化时才删除，避免删除写入 MySQL 期间收到的新上报
+var hdelIfUnchangedScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], ARGV[1]) == ARGV[2] then
+	return redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// flushUserReadingHistory 把某个用户在缓冲区中的阅读上报立即写入 MySQL
+func (articleService *ArticleService) flushUserReadingHistory(userID uint) error {
+	for _, key := range []string{readingHistoryFlushingKey, readingHistoryBufferKey} {
+		fields, err := scanReadingHistoryFields(key, userID)
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			value, err := global.Redis.HGet(key, field).Result()
+			if err != nil {
+				continue
+			}
+			if err := saveReadingHistory(field, value); err != nil {
+				return err
+			}
+			if err := hdelIfUnchangedScript.Run(&global.Redis, []string{key}, field, value).Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// clearReadingHistoryBuffer 删除某个用户在缓冲区中的阅读上报
+func (articleService *ArticleService) clearReadingHistoryBuffer(userID uint) error {
+	for _, key := range []string{readingHistoryBufferKey, readingHistoryFlushingKey} {
+		fields, err := scanReadingHistoryFields(key, userID)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		if err := global.Redis.HDel(key, fields...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanReadingHistoryFields 找出缓冲区中属于某个用户的字段
+func scanReadingHistoryFields(key string, userID uint) ([]string, error) {
+	var fields []string
+	var cursor uint64
+	for {
+		keys, next, err := global.Redis.HScan(key, cursor, readingHistoryField(userID, "*"), 100).Result()
+		if err != nil {
+			return nil, err
+		}
+		// HSCAN 返回的是字段和值交替的列表
+		for i := 0; i < len(keys); i += 2 {
+			fields = append(fields, keys[i])
+		}
+		if next == 0 {
+			return fields, nil
+		}
+		cursor = next
+	}
+}
+
+// saveReadingHistory 把一条阅读上报写入 MySQL，已有的记录只会被更晚的上报覆盖，
+// 因此同一篇文章的上报可以按任意顺序写入，格式错误的上报直接丢弃
+func saveReadingHistory(field, value string) error {
+	userIDStr, articleID, ok := strings.Cut(field, ":")
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	var beacon readingBeacon
+	if !ok || err != nil || json.Unmarshal([]byte(value), &beacon) != nil {
+		global.Log.Warn("Discarding malformed reading history", zap.String("field", field))
+		return nil
+	}
+
+	update := func() error {
+		return global.DB.Model(&database.ReadingHistory{}).
+			Where("user_id = ? AND article_id = ? AND read_at < ?", userID, articleID, beacon.ReadAt).
+			Updates(map[string]interface{}{"progress": beacon.Progress, "position": beacon.Position, "read_at": beacon.ReadAt}).Error
+	}
+	exists := func() (bool, error) {
+		var count int64
+		err := global.DB.Model(&database.ReadingHistory{}).Where("user_id = ? AND article_id = ?", userID, articleID).Count(&count).Error
+		return count > 0, err
+	}
+
+	found, err := exists()
+	if err != nil {
+		return err
+	}
+	if found {
+		return update()
+	}
+	err = global.DB.Create(&database.ReadingHistory{
+		UserID:    uint(userID),
+		ArticleID: articleID,
+		Progress:  beacon.Progress,
+		Position:  beacon.Position,
+		ReadAt:    beacon.ReadAt,
+	}).Error
+	if err != nil {
+		// 并发写入时记录可能已被创建，改为条件更新
+		if found, existsErr := exists(); existsErr == nil && found {
+			return update()
+		}
+	}
+	return err
+}
+
+// readingHistoryField 阅读上报在缓冲区中的字段
+func readingHistoryField(userID uint, articleID string) string {
+	return strconv.FormatUint(uint64(userID), 10) + ":" + articleID
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"server/global"
+	"server/model/database"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SessionService 提供登录会话相关的服务
+type SessionService struct {
+}
+
+var (
+	ErrSessionRevoked     = errors.New("the session has been revoked")
+	ErrRefreshTokenReused = errors.New("the refresh token has been reused")
+)
+
+// rotatedToken 已被轮换的 Refresh Token 所属的会话及轮换时间
+type rotatedToken struct {
+	SessionID uint      `json:"session_id"`
+	RotatedAt time.Time `json:"rotated_at"`
+}
+
+// CreateSession 为新签发的 Refresh Token 创建会话，并在超出最大会话数时下线最早的会话
+func (sessionService *SessionService) CreateSession(userID uint, refreshToken string, claims request.JwtCustomRefreshClaims, ip, userAgent string) error {
+	os, deviceInfo, browserInfo := utils.ParseUserAgent(userAgent)
+	session := database.Session{
+		UserID:       userID,
+		TokenID:      claims.ID,
+		RefreshToken: refreshToken,
+		IP:           ip,
+		OS:           os,
+		DeviceInfo:   deviceInfo,
+		BrowserInfo:  browserInfo,
+		LastActiveAt: time.Now(),
+		ExpiresAt:    claims.ExpiresAt.Time,
+	}
+	if err := global.DB.Create(&session).Error; err != nil {
+		return err
+	}
+
+	// 异步获取登录地址，避免拖慢登录
+	go func() {
+		address := ServiceGroupApp.GaodeService.GetAddressByIP(ip)
+		if err := global.DB.Model(&session).Update("address", address).Error; err != nil {
+			global.Log.Error("Failed to update session address", zap.Error(err))
+		}
+	}()
+
+	return sessionService.evictSessions(userID)
+}
+
+// evictSessions 超出最大会话数时，按最近活跃时间下线最早的会话
+func (sessionService *SessionService) evictSessions(userID uint) error {
+	maxSessions := global.Config.System.MaxSessions
+	if maxSessions <= 0 {
+		return nil
+	}
+
+	var sessions []database.Session
+	if err := global.DB.Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_active_at desc").Order("id desc").
+		Offset(maxSessions).Find(&sessions).Error; err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if err := sessionService.revoke(session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RotateSession 轮换会话的 Refresh Token，返回新的 Refresh Token 及其 Claims
+// 在宽限期内重复使用刚被轮换的旧令牌时不再轮换，返回空字符串；超出宽限期后重复使用旧令牌视为令牌被盗用，会下线整个会话
+func (sessionService *SessionService) RotateSession(claims *request.JwtCustomRefreshClaims, ip string) (string, *request.JwtCustomRefreshClaims, error) {
+	var session database.Session
+	err := global.DB.Where("token_id = ?", claims.ID).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil, sessionService.checkRotatedToken(claims, ip)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	j := utils.NewJWT()
+	newClaims := j.CreateRefreshClaims(request.BaseClaims{UserID: session.UserID})
+	// 轮换不延长会话的有效期
+	newClaims.ExpiresAt = jwt.NewNumericDate(session.ExpiresAt)
+	refreshToken, err := j.CreateRefreshToken(newClaims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// 条件更新，并发请求中只有一个能完成轮换，其余的按宽限期处理
+	result := global.DB.Model(&database.Session{}).
+		Where("id = ? AND token_id = ?", session.ID, claims.ID).
+		Updates(map[string]interface{}{"token_id": newClaims.ID, "refresh_token": refreshToken, "last_active_at": time.Now()})
+	if result.Error != nil {
+		return "", nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "", nil, nil
+	}
+
+	rotatedBytes, _ := json.Marshal(rotatedToken{SessionID: session.ID, RotatedAt: time.Now()})
+	if err := global.Redis.Set(refreshRotatedKey(claims.ID), rotatedBytes, time.Until(session.ExpiresAt)).Err(); err != nil {
+		return "", nil, err
+	}
+	return refreshToken, &newClaims, nil
+}
+
+// checkRotatedToken 检查已经被轮换的旧令牌是否在宽限期内，否则下线整个会话并记录安全事件
+func (sessionService *SessionService) checkRotatedToken(claims *request.JwtCustomRefreshClaims, ip string) error {
+	rotatedBytes, err := global.Redis.Get(refreshRotatedKey(claims.ID)).Bytes()
+	if err != nil {
+		return ErrSessionRevoked
+	}
+	var rotated rotatedToken
+	if err := json.Unmarshal(rotatedBytes, &rotated); err != nil {
+		return err
+	}
+
+	var session database.Session
+	if err := global.DB.Take(&session, rotated.SessionID).Error; err != nil {
+		return ErrSessionRevoked
+	}
+
+	grace, _ := utils.ParseDuration(global.Config.Jwt.RefreshReuseGrace)
+	if time.Since(rotated.RotatedAt) <= grace {
+		return nil
+	}
+
+	global.Log.Warn("Refresh token reuse detected, revoking the session",
+		zap.Uint("user_id", session.UserID),
+		zap.Uint("session_id", session.ID),
+		zap.String("token_id", claims.ID),
+		zap.String("ip", ip),
+	)
+	if err := sessionService.revoke(session); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// SessionList 获取用户当前有效的会话列表，tokenID 为当前请求所属会话的标识
+func (sessionService *SessionService) SessionList(userID uint, tokenID string) ([]response.Session, error) {
+	var sessions []database.Session
+	if err := global.DB.Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_active_at desc").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	list := make([]response.Session, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, response.Session{
+			Session: session,
+			Current: session.TokenID == tokenID,
+		})
+	}
+	return list, nil
+}
+
+// SessionRevoke 下线用户的指定会话
+func (sessionService *SessionService) SessionRevoke(userID, sessionID uint) error {
+	var session database.Session
+	if err := global.DB.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("the session does not exist")
+		}
+		return err
+	}
+	return sessionService.revoke(session)
+}
+
+// SessionRevokeOthers 下线用户除当前会话以外的所有会话
+func (sessionService *SessionService) SessionRevokeOthers(userID uint, tokenID string) error {
+	var sessions []database.Session
+	if err := global.DB.Where("user_id = ? AND token_id <> ?", userID, tokenID).Find(&sessions).Error; err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if err := sessionService.revoke(session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SessionRevokeAll 下线用户的所有会话，用于冻结账户等场景
+func (sessionService *SessionService) SessionRevokeAll(userID uint) error {
+	return sessionService.SessionRevokeOthers(userID, "")
+}
+
+// SessionRevokeByTokenID 下线 Refresh Token 对应的会话，用于退出登录
+func (sessionService *SessionService) SessionRevokeByTokenID(tokenID string) error {
+	var session database.Session
+	if err := global.DB.Where("token_id = ?", tokenID).First(&session).Error; err != nil {
+		return err
+	}
+	return sessionService.revoke(session)
+}
+
+// ClearExpiredSessions 清理已经过期的会话
+func (sessionService *SessionService) ClearExpiredSessions() error {
+	return global.DB.Unscoped().Where("expires_at < ?", time.Now()).Delete(&database.Session{}).Error
+}
+
+// revoke 将会话的 Refresh Token 加入黑名单并删除会话
+func (sessionService *SessionService) revoke(session database.Session) error {
+	if session.RefreshToken != "" && session.ExpiresAt.After(time.Now()) {
+		if err := ServiceGroupApp.JwtService.JoinInBlacklist(database.JwtBlacklist{Jwt: session.RefreshToken}); err != nil {
+			return err
+		}
+	}
+	return global.DB.Unscoped().Delete(&session).Error
+}
+
+// refreshRotatedKey 已被轮换的 Refresh Token 在 Redis 中的键
+func refreshRotatedKey(tokenID string) string {
+	return "refresh_rotated:" + tokenID
+}
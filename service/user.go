@@ -14,15 +14,23 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type UserService struct{}
 
-func (userService *UserService) Register(u database.User) (database.User, error) {
+// Register 注册，先校验密码策略再校验注册验证码，避免验证码在注册失败时被消耗
+func (userService *UserService) Register(u database.User, verificationCode string) (database.User, error) {
 	if !errors.Is(global.DB.Where("email = ?", u.Email).First(&database.User{}).Error, gorm.ErrRecordNotFound) {
 		return database.User{}, errors.New("this email address is already registered, please check the information you filled in, or retrieve your password")
 	}
+	if err := utils.ValidatePassword(u.Password, u.Email, u.Username); err != nil {
+		return database.User{}, err
+	}
+	if err := ServiceGroupApp.BaseService.VerifyEmailCode(u.Email, EmailCodeRegister, verificationCode); err != nil {
+		return database.User{}, err
+	}
 
 	u.Password = utils.BcryptHash(u.Password)
 	u.UUID = uuid.Must(uuid.NewV4())
@@ -32,19 +40,197 @@ func (userService *UserService) Register(u database.User) (database.User, error)
 	if err := global.DB.Create(&u).Error; err != nil {
 		return database.User{}, err
 	}
+
+	// 将该邮箱之前以游客身份发表、并且验证过邮箱的评论关联到新账号，未验证的评论可能是他人冒用邮箱发表的
+	if err := global.DB.Model(&database.Comment{}).
+		Where("guest_email = ? AND user_uuid IS NULL AND guest_verified = ? AND status IN ?", u.Email, true, []appTypes.CommentStatus{appTypes.Published, appTypes.Pending}).
+		Update("user_uuid", u.UUID).Error; err != nil {
+		global.Log.Error("Failed to link guest comments to the new user", zap.Error(err))
+	}
 	return u, nil
 }
 
-func (userService *UserService) EmailLogin(u database.User) (database.User, error) {
+// EmailLogin 邮箱密码登录，登录失败时仍然返回邮箱对应的用户，用于记录登录日志
+func (userService *UserService) EmailLogin(u database.User, ip string) (database.User, error) {
+	// 账户或 IP 被锁定时直接拒绝，不再校验密码
+	if err := userService.LoginLocked(u.Email, ip); err != nil {
+		return database.User{}, err
+	}
+
 	var user database.User
 	err := global.DB.Where("email = ?", u.Email).First(&user).Error
-	if err == nil {
-		if ok := utils.BcryptCheck(u.Password, user.Password); !ok {
-			return database.User{}, errors.New("invalid password")
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return database.User{}, err
+	}
+	if err != nil || !utils.BcryptCheck(u.Password, user.Password) {
+		// 邮箱不存在时同样计入失败次数，避免通过锁定行为探测邮箱是否已注册
+		if err := userService.LoginFailed(u.Email, ip); err != nil {
+			if errors.Is(err, ErrLoginLocked) {
+				return user, err
+			}
+			global.Log.Error("Failed to record failed login", zap.Error(err))
 		}
+		return user, errors.New("invalid email or password")
+	}
+
+	userService.LoginSucceeded(u.Email)
+
+	// 用户确认过异常登录不是本人操作，必须先重置密码
+	if user.PasswordResetRequired {
+		if err := userService.PasswordResetRequest(user.Email); err != nil {
+			global.Log.Error("Failed to send password reset email", zap.Error(err))
+		}
+		return user, ErrPasswordResetRequired
+	}
+	return user, nil
+}
+
+// QQLogin 根据 QQ 的 OpenID 查找用户，首次登录时使用 QQ 资料创建新用户
+func (userService *UserService) QQLogin(accessToken, openID string) (database.User, error) {
+	var user database.User
+	err := global.DB.Where("openid = ?", openID).First(&user).Error
+	if err == nil {
 		return user, nil
 	}
-	return database.User{}, err
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return database.User{}, err
+	}
+
+	userInfo, err := ServiceGroupApp.QQService.GetUserInfoByAccessTokenAndOpenid(accessToken, openID)
+	if err != nil {
+		return database.User{}, err
+	}
+	avatar := userInfo.FigureURLQQ2
+	if avatar == "" {
+		avatar = userInfo.FigureURLQQ1
+	}
+
+	user = database.User{
+		UUID:     uuid.Must(uuid.NewV4()),
+		Username: userInfo.Nickname,
+		Openid:   openID,
+		Avatar:   avatar,
+		RoleID:   appTypes.User,
+		Register: appTypes.QQ,
+	}
+	if err := global.DB.Create(&user).Error; err != nil {
+		return database.User{}, err
+	}
+	return user, nil
+}
+
+// OAuthLogin 根据第三方账号查找已关联的用户，首次登录时使用第三方资料创建新用户
+func (userService *UserService) OAuthLogin(info other.OAuthUserInfo) (database.User, error) {
+	var identity database.UserIdentity
+	err := global.DB.Preload("User").Where("provider = ? AND subject = ?", info.Provider, info.Subject).First(&identity).Error
+	if err == nil {
+		return identity.User, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return database.User{}, err
+	}
+
+	// 只保存提供方确认已验证的邮箱，避免冒用他人尚未注册的邮箱
+	email := ""
+	if info.EmailVerified {
+		email = info.Email
+	}
+
+	// 邮箱已被其他账号使用时，不能自动合并，需要用户登录后手动关联，避免账号被冒用
+	if email != "" && !errors.Is(global.DB.Where("email = ?", email).First(&database.User{}).Error, gorm.ErrRecordNotFound) {
+		return database.User{}, errors.New("this email address is already registered, please log in and link this account in your settings")
+	}
+
+	provider, _ := global.Config.OAuth.Provider(info.Provider)
+	username := info.Name
+	if username == "" {
+		username = info.Provider + "_" + info.Subject
+	}
+	avatar := info.Avatar
+	if avatar == "" {
+		avatar = "./images/avatar.png"
+	}
+
+	user := database.User{
+		UUID:     uuid.Must(uuid.NewV4()),
+		Username: username,
+		Email:    email,
+		Avatar:   avatar,
+		RoleID:   appTypes.User,
+		Register: appTypes.ToOAuthRegister(provider.Type),
+	}
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&database.UserIdentity{
+			UserID:   user.ID,
+			Provider: info.Provider,
+			Subject:  info.Subject,
+			Email:    info.Email,
+			Name:     info.Name,
+		}).Error
+	})
+	if err != nil {
+		return database.User{}, err
+	}
+	return user, nil
+}
+
+// OAuthLink 将第三方账号关联到当前用户
+func (userService *UserService) OAuthLink(userID uint, info other.OAuthUserInfo) error {
+	var identity database.UserIdentity
+	err := global.DB.Where("provider = ? AND subject = ?", info.Provider, info.Subject).First(&identity).Error
+	if err == nil {
+		if identity.UserID == userID {
+			return nil
+		}
+		return errors.New("this account has been linked to another user")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if !errors.Is(global.DB.Where("user_id = ? AND provider = ?", userID, info.Provider).First(&database.UserIdentity{}).Error, gorm.ErrRecordNotFound) {
+		return errors.New("another account of this provider has already been linked, please unlink it first")
+	}
+
+	return global.DB.Create(&database.UserIdentity{
+		UserID:   userID,
+		Provider: info.Provider,
+		Subject:  info.Subject,
+		Email:    info.Email,
+		Name:     info.Name,
+	}).Error
+}
+
+// OAuthUnlink 解除当前用户与第三方账号的关联，至少需要保留一种登录方式
+func (userService *UserService) OAuthUnlink(userID uint, provider string) error {
+	var identity database.UserIdentity
+	if err := global.DB.Where("user_id = ? AND provider = ?", userID, provider).First(&identity).Error; err != nil {
+		return errors.New("this provider has not been linked")
+	}
+
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return err
+	}
+	var count int64
+	if err := global.DB.Model(&database.UserIdentity{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count <= 1 && user.Password == "" && user.Openid == "" {
+		return errors.New("this is your only login method, please set a password or link another account first")
+	}
+
+	return global.DB.Unscoped().Delete(&identity).Error
+}
+
+// OAuthIdentities 获取当前用户已关联的第三方账号
+func (userService *UserService) OAuthIdentities(userID uint) ([]database.UserIdentity, error) {
+	var identities []database.UserIdentity
+	err := global.DB.Where("user_id = ?", userID).Order("id").Find(&identities).Error
+	return identities, err
 }
 
 func (userService *UserService) ForgotPassword(req request.ForgotPassword) error {
@@ -53,8 +239,14 @@ func (userService *UserService) ForgotPassword(req request.ForgotPassword) error
 	if err != nil {
 		return errors.New("invalid email")
 	}
+	if err := utils.ValidatePassword(req.NewPassword, user.Email, user.Username); err != nil {
+		return err
+	}
 	user.Password = utils.BcryptHash(req.NewPassword)
-	return global.DB.Save(&user).Error
+	if err := global.DB.Save(&user).Error; err != nil {
+		return err
+	}
+	return userService.afterPasswordReset(user)
 }
 
 func (userService *UserService) UserCard(uuid string) (response.UserCard, error) {
@@ -63,29 +255,36 @@ func (userService *UserService) UserCard(uuid string) (response.UserCard, error)
 	if err != nil {
 		return response.UserCard{}, err
 	}
-	return response.UserCard{
-		UUID:      user.UUID,
-		Username:  user.Username,
-		Avatar:    user.Avatar,
-		Address:   user.Address,
-		Signature: user.Signature,
-	}, nil
+	card := userCard(user)
+	card.FollowerCount, card.FollowingCount, err = ServiceGroupApp.FollowService.FollowCounts(user)
+	if err != nil {
+		return response.UserCard{}, err
+	}
+	return card, nil
 }
 
 func (userService *UserService) Logout(c *gin.Context) {
-	uuid := utils.GetUUID(c)
 	jwtStr := utils.GetRefreshToken(c)
 	utils.ClearRefreshToken(c)
-	global.Redis.Del(uuid.String())
+	if claims, err := utils.GetRefreshClaims(c); err == nil && claims.ID != "" {
+		if err := ServiceGroupApp.SessionService.SessionRevokeByTokenID(claims.ID); err == nil {
+			return
+		}
+	}
 	_ = ServiceGroupApp.JwtService.JoinInBlacklist(database.JwtBlacklist{Jwt: jwtStr})
 }
 
 func (userService *UserService) UserResetPassword(req request.UserResetPassword) error {
 	var user database.User
-	err := global.DB.Where("password=?", utils.BcryptHash(req.Password)).First(&user)
-	if err != nil || user.ID != req.UserID {
+	if err := global.DB.Take(&user, req.UserID).Error; err != nil {
+		return err
+	}
+	if !utils.BcryptCheck(req.Password, user.Password) {
 		return errors.New("invalid password")
 	}
+	if err := utils.ValidatePassword(req.NewPassword, user.Email, user.Username); err != nil {
+		return err
+	}
 	user.Password = utils.BcryptHash(req.NewPassword)
 	return global.DB.Save(&user).Error
 }
@@ -180,12 +379,7 @@ func (userService *UserService) UserFreeze(req request.UserOperation) error {
 		return err
 	}
 
-	jwtStr, _ := ServiceGroupApp.JwtService.GetRedisJWT(user.UUID)
-	if jwtStr != "" {
-		_ = ServiceGroupApp.JwtService.JoinInBlacklist(database.JwtBlacklist{Jwt: jwtStr})
-	}
-
-	return nil
+	return ServiceGroupApp.SessionService.SessionRevokeAll(user.ID)
 }
 
 func (userService *UserService) UserUnfreeze(req request.UserOperation) error {
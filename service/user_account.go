@@ -0,0 +1,182 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"server/eventbus"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/request"
+	"server/utils"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// deletedUserNickname 注销用户的评论展示的昵称
+const deletedUserNickname = "已注销用户"
+
+// UserChangeEmail 校验新邮箱的验证码后修改邮箱，并通知旧邮箱
+func (userService *UserService) UserChangeEmail(req request.UserChangeEmail) error {
+	var user database.User
+	if err := global.DB.Take(&user, req.UserID).Error; err != nil {
+		return err
+	}
+	if user.Password != "" && !utils.BcryptCheck(req.Password, user.Password) {
+		return errors.New("invalid password")
+	}
+	if normalizeEmail(req.Email) == normalizeEmail(user.Email) {
+		return errors.New("the new email address is the same as the current one")
+	}
+	if !errors.Is(global.DB.Where("email = ?", req.Email).First(&database.User{}).Error, gorm.ErrRecordNotFound) {
+		return errors.New("this email address is already registered")
+	}
+	if err := ServiceGroupApp.BaseService.VerifyEmailCode(req.Email, EmailCodeChangeEmail, req.VerificationCode); err != nil {
+		return err
+	}
+
+	oldEmail := user.Email
+	if err := global.DB.Model(&user).Update("email", req.Email).Error; err != nil {
+		return err
+	}
+
+	if oldEmail != "" {
+		subject := "您的账户邮箱已修改"
+		body := `亲爱的用户[` + user.Username + `]，<br/>
+<br/>
+您在` + global.Config.Website.Title + `的账户邮箱已于 ` + time.Now().Format("2006-01-02 15:04:05") + ` 修改为 ` + maskEmail(req.Email) + `。<br/>
+<br/>
+如果这不是您本人的操作，请立即联系我们的支持团队：` + global.Config.Email.From + `<br/>
+<br/>
+祝好，<br/>` +
+			global.Config.Website.Title + `<br/>
+<br/>`
+		go func() {
+			if err := utils.Email(oldEmail, subject, body); err != nil {
+				global.Log.Error("Failed to notify the old email address", zap.Error(err))
+			}
+		}()
+	}
+	return nil
+}
+
+// UserDeletionRequest 申请注销账户，冷静期结束后账户会被删除
+func (userService *UserService) UserDeletionRequest(userID uint, password string) (time.Time, error) {
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return time.Time{}, err
+	}
+	if user.Password != "" && !utils.BcryptCheck(password, user.Password) {
+		return time.Time{}, errors.New("invalid password")
+	}
+	if user.DeletionScheduledAt != nil {
+		return *user.DeletionScheduledAt, nil
+	}
+
+	delay, err := utils.ParseDuration(global.Config.Security.DeletionDelay)
+	if err != nil {
+		return time.Time{}, err
+	}
+	scheduledAt := time.Now().Add(delay)
+	if err := global.DB.Model(&user).Update("deletion_scheduled_at", scheduledAt).Error; err != nil {
+		return time.Time{}, err
+	}
+	return scheduledAt, nil
+}
+
+// UserDeletionCancel 在冷静期内撤销注销申请
+func (userService *UserService) UserDeletionCancel(userID uint) error {
+	return global.DB.Model(&database.User{}).Where("id = ?", userID).Update("deletion_scheduled_at", nil).Error
+}
+
+// DeleteScheduledUsers 删除冷静期已结束的账户
+func (userService *UserService) DeleteScheduledUsers() error {
+	var users []database.User
+	if err := global.DB.Where("deletion_scheduled_at <= ?", time.Now()).Find(&users).Error; err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err := userService.deleteUser(user); err != nil {
+			global.Log.Error("Failed to delete user", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// deleteUser 删除账户：匿名化评论和反馈，删除收藏并修正 ES 中的收藏量，下线所有会话，清除登录记录
+func (userService *UserService) deleteUser(user database.User) error {
+	var likes []database.ArticleLike
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&database.Comment{}).Where("user_uuid = ?", user.UUID).
+			Updates(map[string]interface{}{"user_uuid": nil, "guest_nickname": deletedUserNickname, "guest_email": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&database.Comment{}).Where("guest_email = ?", user.Email).Update("guest_email", "").Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&database.Feedback{}).Where("user_uuid = ?", user.UUID).Update("user_uuid", nil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Find(&likes).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{
+			&database.ArticleLike{},
+			&database.Login{},
+			&database.UserIdentity{},
+			&database.UserTwoFactor{},
+			&database.RecoveryCode{},
+			&database.UserRole{},
+			&database.PersonalAccessToken{},
+			&database.Follow{},
+			&database.BookmarkFolder{},
+			&database.ReadingHistory{},
+		} {
+			if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("target_type = ? AND target = ?", appTypes.FollowUser, user.UUID.String()).Delete(&database.Follow{}).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 修正 ES 中文章的收藏量
+	for _, like := range likes {
+		msgBytes, _ := json.Marshal(eventbus.ESEvent{ArticleID: like.ArticleID, Field: "likes", Delta: -1})
+		if err := global.EventBus.Publish(eventbus.TopicESUpdate, msgBytes); err != nil {
+			global.Log.Error("Failed to publish ES update", zap.String("article_id", like.ArticleID), zap.Error(err))
+		}
+	}
+
+	// 删除还没有写入 MySQL 的阅读记录
+	if err := ServiceGroupApp.ArticleService.clearReadingHistoryBuffer(user.ID); err != nil {
+		global.Log.Error("Failed to clear reading history buffer", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+
+	// 下线所有会话后删除账户
+	if err := ServiceGroupApp.SessionService.SessionRevokeAll(user.ID); err != nil {
+		return err
+	}
+	return global.DB.Unscoped().Delete(&user).Error
+}
+
+// maskEmail 隐藏邮箱的部分字符，用于通知邮件
+func maskEmail(email string) string {
+	for i := range email {
+		if email[i] == '@' {
+			if i <= 2 {
+				return email[:1] + "***" + email[i:]
+			}
+			return email[:2] + "***" + email[i:]
+		}
+	}
+	return email
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"server/eventbus"
+	"server/global"
+	"server/model/database"
+	"server/utils"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+// userExportInterval 两次导出申请之间的最小间隔
+const userExportInterval = time.Hour
+
+// userExportTimeLayout 导出文件中的时间格式
+const userExportTimeLayout = "2006-01-02 15:04:05"
+
+// UserExportData 导出给用户的个人数据
+type UserExportData struct {
+	ExportedAt time.Time            `json:"exported_at"` // 导出时间
+	Profile    database.User        `json:"profile"`     // 个人资料
+	Comments   []UserExportComment  `json:"comments"`    // 发表的评论
+	Feedbacks  []UserExportFeedback `json:"feedbacks"`   // 提交的反馈及回复
+	Likes      []UserExportLike     `json:"likes"`       // 收藏的文章
+	Logins     []UserExportLogin    `json:"logins"`      // 登录记录
+}
+
+// UserExportComment 导出的评论
+type UserExportComment struct {
+	ID        uint      `json:"id"`         // 评论 ID
+	ArticleID string    `json:"article_id"` // 文章 ID
+	PID       *uint     `json:"p_id"`       // 父评论 ID
+	Content   string    `json:"content"`    // 内容
+	Status    string    `json:"status"`     // 评论状态
+	CreatedAt time.Time `json:"created_at"` // 发表时间
+}
+
+// UserExportFeedback 导出的反馈
+type UserExportFeedback struct {
+	ID        uint      `json:"id"`         // 反馈 ID
+	Content   string    `json:"content"`    // 内容
+	Reply     string    `json:"reply"`      // 回复
+	CreatedAt time.Time `json:"created_at"` // 提交时间
+}
+
+// UserExportLike 导出的收藏
+type UserExportLike struct {
+	ArticleID string    `json:"article_id"` // 文章 ID
+	Title     string    `json:"title"`      // 文章标题，文章已删除时为空
+	Note      string    `json:"note"`       // 私人备注
+	CreatedAt time.Time `json:"created_at"` // 收藏时间
+}
+
+// UserExportLogin 导出的登录记录
+type UserExportLogin struct {
+	LoginMethod string    `json:"login_method"` // 登录方式
+	IP          string    `json:"ip"`           // IP 地址
+	Address     string    `json:"address"`      // 登录地址
+	OS          string    `json:"os"`           // 操作系统
+	DeviceInfo  string    `json:"device_info"`  // 设备信息
+	BrowserInfo string    `json:"browser_info"` // 浏览器信息
+	Status      int       `json:"status"`       // 登录状态
+	CreatedAt   time.Time `json:"created_at"`   // 登录时间
+}
+
+// UserExportRequest 申请导出个人数据，导出任务通过消息队列异步执行，完成后以邮件发送下载链接
+func (userService *UserService) UserExportRequest(userID uint) error {
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return errors.New("please bind an email address before exporting your data")
+	}
+
+	ok, err := global.Redis.SetNX(userExportLockKey(userID), time.Now().Unix(), userExportInterval).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("an export was requested recently, please try again later")
+	}
+
+	msgBytes, err := json.Marshal(eventbus.UserExportEvent{UserID: userID})
+	if err != nil {
+		global.Redis.Del(userExportLockKey(userID))
+		return err
+	}
+	if err := global.EventBus.Publish(eventbus.TopicUserExport, msgBytes); err != nil {
+		global.Redis.Del(userExportLockKey(userID))
+		return err
+	}
+	return nil
+}
+
+// HandleUserExports 处理用户数据导出任务，失败的任务直接丢弃，用户可以重新申请
+func (userService *UserService) HandleUserExports(msgs []eventbus.Message) {
+	for _, msg := range msgs {
+		var event eventbus.UserExportEvent
+		if err := json.Unmarshal(msg.Body(), &event); err != nil {
+			global.Log.Error("消息解析失败:", zap.Error(err))
+			msg.Fail("invalid message: "+err.Error(), false)
+			continue
+		}
+		if err := userService.UserExport(event); err != nil {
+			global.Log.Error("Failed to export user data", zap.Uint("user_id", event.UserID), zap.Error(err))
+			msg.Fail(err.Error(), false)
+			continue
+		}
+		msg.Ack()
+	}
+}
+
+// UserExport 生成用户的个人数据导出文件，并以邮件发送限时下载链接
+func (userService *UserService) UserExport(event eventbus.UserExportEvent) error {
+	var user database.User
+	if err := global.DB.Take(&user, event.UserID).Error; err != nil {
+		return err
+	}
+	data, err := userService.userExportData(user)
+	if err != nil {
+		return err
+	}
+
+	expiry, err := utils.ParseDuration(global.Config.Export.Expiry)
+	if err != nil {
+		return err
+	}
+	filename, err := userService.writeUserExport(data)
+	if err != nil {
+		return err
+	}
+
+	token, err := utils.GenerateRandomKey(32)
+	if err != nil {
+		return err
+	}
+	if err := global.Redis.Set(userExportKey(utils.SHA256Hex(token)), filename, expiry).Err(); err != nil {
+		return err
+	}
+
+	link := utils.APIURL("user/exportDownload", url.Values{"token": {token}})
+	subject := "您的个人数据导出已完成"
+	body := `亲爱的用户[` + user.Username + `]，<br/>
+<br/>
+您在` + global.Config.Website.Title + `申请的个人数据导出已完成，请点击下面的链接下载：<br/>
+<br/>
+<a href="` + link + `">` + link + `</a><br/>
+<br/>
+该链接在 ` + global.Config.Export.Expiry + ` 内有效，压缩包中包含 JSON 和 CSV 两种格式的数据。请妥善保管，不要转发给他人。<br/>
+<br/>
+如果您没有申请导出数据，请立即修改密码并联系我们的支持团队：` + global.Config.Email.From + `<br/>
+<br/>
+祝好，<br/>` +
+		global.Config.Website.Title + `<br/>
+<br/>`
+	return utils.Email(user.Email, subject, body)
+}
+
+// UserExportFile 根据下载链接中的令牌获取导出文件的路径
+func (userService *UserService) UserExportFile(token string) (string, error) {
+	filename, err := global.Redis.Get(userExportKey(utils.SHA256Hex(token))).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", errors.New("the download link is invalid or has expired")
+	}
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(global.Config.Export.Path, filepath.Base(filename))
+	if _, err := os.Stat(path); err != nil {
+		return "", errors.New("the download link is invalid or has expired")
+	}
+	return path, nil
+}
+
+// ClearExpiredUserExports 删除超过有效期的导出文件
+func (userService *UserService) ClearExpiredUserExports() error {
+	expiry, err := utils.ParseDuration(global.Config.Export.Expiry)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(global.Config.Export.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".zip" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < expiry {
+			continue
+		}
+		if err := os.Remove(filepath.Join(global.Config.Export.Path, entry.Name())); err != nil {
+			global.Log.Error("Failed to remove expired export file", zap.String("file", entry.Name()), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// userExportData 收集用户的个人数据
+func (userService *UserService) userExportData(user database.User) (UserExportData, error) {
+	data := UserExportData{
+		ExportedAt: time.Now(),
+		Profile:    user,
+		Comments:   []UserExportComment{},
+		Feedbacks:  []UserExportFeedback{},
+		Likes:      []UserExportLike{},
+		Logins:     []UserExportLogin{},
+	}
+
+	var comments []database.Comment
+	if err := global.DB.Where("user_uuid = ?", user.UUID).Order("id").Find(&comments).Error; err != nil {
+		return data, err
+	}
+	for _, comment := range comments {
+		data.Comments = append(data.Comments, UserExportComment{
+			ID:        comment.ID,
+			ArticleID: comment.ArticleID,
+			PID:       comment.PID,
+			Content:   comment.Content,
+			Status:    comment.Status.String(),
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+
+	var feedbacks []database.Feedback
+	if err := global.DB.Where("user_uuid = ?", user.UUID).Order("id").Find(&feedbacks).Error; err != nil {
+		return data, err
+	}
+	for _, feedback := range feedbacks {
+		data.Feedbacks = append(data.Feedbacks, UserExportFeedback{
+			ID:        feedback.ID,
+			Content:   feedback.Content,
+			Reply:     feedback.Reply,
+			CreatedAt: feedback.CreatedAt,
+		})
+	}
+
+	var likes []database.ArticleLike
+	if err := global.DB.Where("user_id = ?", user.ID).Order("id").Find(&likes).Error; err != nil {
+		return data, err
+	}
+	articleIDs := make([]string, 0, len(likes))
+	for _, like := range likes {
+		articleIDs = append(articleIDs, like.ArticleID)
+	}
+	articles, err := ServiceGroupApp.ArticleService.MGet(articleIDs)
+	if err != nil {
+		return data, err
+	}
+	for _, like := range likes {
+		// 文章可能已被删除，此时只导出文章 ID
+		data.Likes = append(data.Likes, UserExportLike{
+			ArticleID: like.ArticleID,
+			Title:     articles[like.ArticleID].Title,
+			Note:      like.Note,
+			CreatedAt: like.CreatedAt,
+		})
+	}
+
+	var logins []database.Login
+	if err := global.DB.Where("user_id = ?", user.ID).Order("id").Find(&logins).Error; err != nil {
+		return data, err
+	}
+	for _, login := range logins {
+		data.Logins = append(data.Logins, UserExportLogin{
+			LoginMethod: login.LoginMethod,
+			IP:          login.IP,
+			Address:     login.Address,
+			OS:          login.OS,
+			DeviceInfo:  login.DeviceInfo,
+			BrowserInfo: login.BrowserInfo,
+			Status:      login.Status,
+			CreatedAt:   login.CreatedAt,
+		})
+	}
+	return data, nil
+}
+
+// writeUserExport 将个人数据写入 zip 压缩包，返回压缩包的文件名
+func (userService *UserService) writeUserExport(data UserExportData) (string, error) {
+	if err := os.MkdirAll(global.Config.Export.Path, os.ModePerm); err != nil {
+		return "", err
+	}
+	name, err := utils.GenerateRandomKey(16)
+	if err != nil {
+		return "", err
+	}
+	filename := strconv.FormatUint(uint64(data.Profile.ID), 10) + "-" + utils.SHA256Hex(name)[:32] + ".zip"
+	path := filepath.Join(global.Config.Export.Path, filename)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	if err := writeUserExportZip(file, data); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return filename, nil
+}
+
+// writeUserExportZip 写入 data.json 以及各类数据对应的 CSV 文件
+func writeUserExportZip(file *os.File, data UserExportData) error {
+	zw := zip.NewWriter(file)
+
+	w, err := zw.Create("data.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return err
+	}
+
+	user := data.Profile
+	profile := [][]string{
+		{"uuid", "username", "email", "avatar", "address", "signature", "register", "created_at"},
+		{user.UUID.String(), user.Username, user.Email, user.Avatar, user.Address, user.Signature, user.Register.String(), user.CreatedAt.Format(userExportTimeLayout)},
+	}
+
+	comments := [][]string{{"id", "article_id", "parent_id", "content", "status", "created_at"}}
+	for _, comment := range data.Comments {
+		pid := ""
+		if comment.PID != nil {
+			pid = strconv.FormatUint(uint64(*comment.PID), 10)
+		}
+		comments = append(comments, []string{strconv.FormatUint(uint64(comment.ID), 10), comment.ArticleID, pid, comment.Content, comment.Status, comment.CreatedAt.Format(userExportTimeLayout)})
+	}
+
+	feedbacks := [][]string{{"id", "content", "reply", "created_at"}}
+	for _, feedback := range data.Feedbacks {
+		feedbacks = append(feedbacks, []string{strconv.FormatUint(uint64(feedback.ID), 10), feedback.Content, feedback.Reply, feedback.CreatedAt.Format(userExportTimeLayout)})
+	}
+
+	likes := [][]string{{"article_id", "title", "note", "created_at"}}
+	for _, like := range data.Likes {
+		likes = append(likes, []string{like.ArticleID, like.Title, like.Note, like.CreatedAt.Format(userExportTimeLayout)})
+	}
+
+	logins := [][]string{{"login_method", "ip", "address", "os", "device_info", "browser_info", "status", "created_at"}}
+	for _, login := range data.Logins {
+		logins = append(logins, []string{login.LoginMethod, login.IP, login.Address, login.OS, login.DeviceInfo, login.BrowserInfo, strconv.Itoa(login.Status), login.CreatedAt.Format(userExportTimeLayout)})
+	}
+
+	for _, f := range []struct {
+		name    string
+		records [][]string
+	}{
+		{"profile.csv", profile},
+		{"comments.csv", comments},
+		{"feedback.csv", feedbacks},
+		{"likes.csv", likes},
+		{"logins.csv", logins},
+	} {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(f.records); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// userExportKey 导出文件下载令牌在 Redis 中的键
+func userExportKey(hash string) string {
+	return "user_export:" + hash
+}
+
+// userExportLockKey 限制用户频繁申请导出的键
+func userExportLockKey(userID uint) string {
+	return "user_export_lock:" + strconv.FormatUint(uint64(userID), 10)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"server/config"
+	"server/global"
+	"server/model/database"
+	"server/utils"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrLoginLocked 登录失败次数过多，账户或 IP 已被锁定
+var ErrLoginLocked = errors.New("too many failed login attempts")
+
+// 锁定策略未配置或配置无效时使用的默认值
+const (
+	defaultLockoutWindow   = 15 * time.Minute
+	defaultLockDuration    = 5 * time.Minute
+	defaultMaxLockDuration = 24 * time.Hour
+)
+
+// LoginLockedError 账户或 IP 被锁定时返回的错误，包含剩余的锁定时间
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, please try again in %s", ErrLoginLocked.Error(), e.RetryAfter.Round(time.Second))
+}
+
+func (e LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
+
+// LoginLocked 检查账户或 IP 是否被锁定
+func (userService *UserService) LoginLocked(email, ip string) error {
+	if !global.Config.Security.Lockout.Enable {
+		return nil
+	}
+	for _, subject := range lockoutSubjects(email, ip) {
+		ttl, err := global.Redis.TTL(loginLockKey(subject)).Result()
+		if err == nil && ttl > 0 {
+			return LoginLockedError{RetryAfter: ttl}
+		}
+	}
+	return nil
+}
+
+// LoginFailed 记录一次登录失败，账户或 IP 在统计窗口内失败次数达到上限时锁定，每次锁定的时长翻倍
+func (userService *UserService) LoginFailed(email, ip string) error {
+	lockout := global.Config.Security.Lockout
+	if !lockout.Enable {
+		return nil
+	}
+	window, lockDuration, maxLockDuration := lockoutDurations(lockout)
+
+	// 记录账户登录失败时使用的 IP，解除账户锁定时一并解除这些 IP 的锁定
+	ipsKey := loginFailIPsKey("account:" + normalizeEmail(email))
+	global.Redis.SAdd(ipsKey, ip)
+	global.Redis.Expire(ipsKey, 2*maxLockDuration)
+
+	var lockedErr error
+	for subject, maxAttempts := range map[string]int{"account:" + normalizeEmail(email): lockout.MaxAttempts, "ip:" + ip: lockout.IPMaxAttempts} {
+		if maxAttempts <= 0 {
+			continue
+		}
+		failKey := loginFailKey(subject)
+		count, err := global.Redis.Incr(failKey).Result()
+		if err != nil {
+			return err
+		}
+		if count == 1 {
+			global.Redis.Expire(failKey, window)
+		}
+		if count < int64(maxAttempts) {
+			continue
+		}
+
+		// 达到失败上限，按照锁定次数计算锁定时长，并重新开始计数
+		level, err := global.Redis.Incr(loginLockLevelKey(subject)).Result()
+		if err != nil {
+			return err
+		}
+		global.Redis.Expire(loginLockLevelKey(subject), 2*maxLockDuration)
+
+		duration := lockoutDuration(level, lockDuration, maxLockDuration)
+		global.Redis.Set(loginLockKey(subject), 1, duration)
+		global.Redis.Del(failKey)
+		lockedErr = LoginLockedError{RetryAfter: duration}
+	}
+	return lockedErr
+}
+
+// lockoutDurations 解析统计窗口、首次锁定时长和最长锁定时长，未配置或无效时使用默认值，
+// 时长为 0 的锁定不会过期，因此必须保证每个时长都大于 0
+func lockoutDurations(lockout config.Lockout) (window, lockDuration, maxLockDuration time.Duration) {
+	parse := func(name, value string, def time.Duration) time.Duration {
+		d, err := utils.ParseDuration(value)
+		if err != nil || d <= 0 {
+			global.Log.Warn("Invalid lockout duration, using the default value", zap.String("name", name), zap.String("value", value), zap.Duration("default", def))
+			return def
+		}
+		return d
+	}
+	window = parse("window", lockout.Window, defaultLockoutWindow)
+	lockDuration = parse("lock_duration", lockout.LockDuration, defaultLockDuration)
+	maxLockDuration = parse("max_lock_duration", lockout.MaxLockDuration, defaultMaxLockDuration)
+	if maxLockDuration < lockDuration {
+		maxLockDuration = lockDuration
+	}
+	return window, lockDuration, maxLockDuration
+}
+
+// lockoutDuration 计算第 level 次锁定的时长，从首次锁定时长开始每次翻倍，不超过最长锁定时长
+func lockoutDuration(level int64, lockDuration, maxLockDuration time.Duration) time.Duration {
+	duration := lockDuration
+	for i := int64(1); i < level && duration < maxLockDuration; i++ {
+		duration *= 2
+	}
+	if duration > maxLockDuration {
+		duration = maxLockDuration
+	}
+	return duration
+}
+
+// LoginSucceeded 登录成功后清除账户的失败次数和锁定等级
+func (userService *UserService) LoginSucceeded(email string) {
+	subject := "account:" + normalizeEmail(email)
+	global.Redis.Del(loginFailKey(subject), loginLockLevelKey(subject))
+}
+
+// UserUnlock 解除账户的登录锁定，以及该账户登录失败时使用过的 IP 的锁定
+func (userService *UserService) UserUnlock(userID uint) error {
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return err
+	}
+	subject := "account:" + normalizeEmail(user.Email)
+	ips, err := global.Redis.SMembers(loginFailIPsKey(subject)).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{loginLockKey(subject), loginFailKey(subject), loginLockLevelKey(subject), loginFailIPsKey(subject)}
+	for _, ip := range ips {
+		keys = append(keys, loginLockKey("ip:"+ip), loginFailKey("ip:"+ip), loginLockLevelKey("ip:"+ip))
+	}
+	return global.Redis.Del(keys...).Err()
+}
+
+// lockoutSubjects 登录锁定的计数对象，分别为账户和 IP
+func lockoutSubjects(email, ip string) []string {
+	return []string{"account:" + normalizeEmail(email), "ip:" + ip}
+}
+
+// normalizeEmail 统一邮箱的大小写，避免通过修改大小写绕过锁定
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginFailKey(subject string) string {
+	return "login_fail:" + subject
+}
+
+func loginLockKey(subject string) string {
+	return "login_lock:" + subject
+}
+
+func loginLockLevelKey(subject string) string {
+	return "login_lock_level:" + subject
+}
+
+func loginFailIPsKey(subject string) string {
+	return "login_fail_ips:" + subject
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"server/config"
+	"server/global"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   int64
+		lock    time.Duration
+		maxLock time.Duration
+		want    time.Duration
+	}{
+		{"first lock", 1, 5 * time.Minute, 24 * time.Hour, 5 * time.Minute},
+		{"second lock doubles", 2, 5 * time.Minute, 24 * time.Hour, 10 * time.Minute},
+		{"fifth lock", 5, 5 * time.Minute, 24 * time.Hour, 80 * time.Minute},
+		{"capped at max", 10, 5 * time.Minute, 24 * time.Hour, 24 * time.Hour},
+		{"very high level does not overflow", 1 << 40, 5 * time.Minute, 24 * time.Hour, 24 * time.Hour},
+		{"max equals lock", 3, time.Hour, time.Hour, time.Hour},
+		{"zero level uses first lock", 0, 5 * time.Minute, 24 * time.Hour, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lockoutDuration(tt.level, tt.lock, tt.maxLock); got != tt.want {
+				t.Errorf("lockoutDuration() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutDurations(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	tests := []struct {
+		name        string
+		lockout     config.Lockout
+		wantWindow  time.Duration
+		wantLock    time.Duration
+		wantMaxLock time.Duration
+	}{
+		{"configured", config.Lockout{Window: "15m", LockDuration: "5m", MaxLockDuration: "1d"}, 15 * time.Minute, 5 * time.Minute, 24 * time.Hour},
+		{"missing values use defaults", config.Lockout{}, defaultLockoutWindow, defaultLockDuration, defaultMaxLockDuration},
+		{"invalid values use defaults", config.Lockout{Window: "soon", LockDuration: "x", MaxLockDuration: "forever"}, defaultLockoutWindow, defaultLockDuration, defaultMaxLockDuration},
+		{"zero values use defaults", config.Lockout{Window: "0s", LockDuration: "0m", MaxLockDuration: "0h"}, defaultLockoutWindow, defaultLockDuration, defaultMaxLockDuration},
+		{"max shorter than lock is raised", config.Lockout{Window: "10m", LockDuration: "2h", MaxLockDuration: "1h"}, 10 * time.Minute, 2 * time.Hour, 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, lock, maxLock := lockoutDurations(tt.lockout)
+			if window != tt.wantWindow || lock != tt.wantLock || maxLock != tt.wantMaxLock {
+				t.Errorf("lockoutDurations() = (%s, %s, %s), want (%s, %s, %s)", window, lock, maxLock, tt.wantWindow, tt.wantLock, tt.wantMaxLock)
+			}
+			// 锁定时长为 0 时 Redis 中的锁不会过期
+			if lockoutDuration(1, lock, maxLock) <= 0 {
+				t.Errorf("lockoutDuration() must be positive")
+			}
+		})
+	}
+}
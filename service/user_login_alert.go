@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"html"
+	"net/url"
+	"server/global"
+	"server/model/database"
+	"server/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrPasswordResetRequired 用户确认过异常登录不是本人操作，必须重置密码后才能使用密码登录
+var ErrPasswordResetRequired = errors.New("a password reset is required, a reset link has been sent to your email")
+
+// LoginAlert 登录成功后检查设备和城市是否在历史登录记录中出现过，是新的设备或城市时发送提醒邮件
+func (userService *UserService) LoginAlert(login database.Login) error {
+	cfg := global.Config.Security.LoginAlert
+	if !cfg.Enable || (!cfg.NewDevice && !cfg.NewLocation) {
+		return nil
+	}
+
+	window, err := utils.ParseDuration(cfg.HistoryWindow)
+	if err != nil {
+		return err
+	}
+	var history []database.Login
+	if err := global.DB.Where("user_id = ? AND status = ? AND id <> ? AND created_at > ?", login.UserID, login.Status, login.ID, time.Now().Add(-window)).
+		Select("os", "device_info", "browser_info", "address").Find(&history).Error; err != nil {
+		return err
+	}
+	if len(history) < cfg.MinHistory || len(history) == 0 {
+		return nil
+	}
+
+	newDevice, newLocation := cfg.NewDevice, cfg.NewLocation && login.Address != "" && login.Address != "未知"
+	for _, h := range history {
+		if h.OS == login.OS && h.DeviceInfo == login.DeviceInfo && h.BrowserInfo == login.BrowserInfo {
+			newDevice = false
+		}
+		if h.Address == login.Address {
+			newLocation = false
+		}
+	}
+	if !newDevice && !newLocation {
+		return nil
+	}
+
+	var user database.User
+	if err := global.DB.Take(&user, login.UserID).Error; err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return nil
+	}
+
+	expiry, err := utils.ParseDuration(cfg.LinkExpiry)
+	if err != nil {
+		return err
+	}
+	token, err := utils.GenerateRandomKey(32)
+	if err != nil {
+		return err
+	}
+	if err := global.Redis.Set(loginAlertKey(utils.SHA256Hex(token)), strconv.FormatUint(uint64(user.ID), 10), expiry).Err(); err != nil {
+		return err
+	}
+
+	var reasons []string
+	if newDevice {
+		reasons = append(reasons, "新的设备")
+	}
+	if newLocation {
+		reasons = append(reasons, "新的地点")
+	}
+	link := utils.SiteURL("login-alert", url.Values{"token": {token}})
+	subject := "您的账户在" + strings.Join(reasons, "和") + "登录"
+	// 设备信息来自 User-Agent，可能由攻击者构造，写入邮件前需要转义
+	body := `亲爱的用户[` + html.EscapeString(user.Username) + `]，<br/>
+<br/>
+您在` + global.Config.Website.Title + `的账户刚刚在` + strings.Join(reasons, "和") + `登录：<br/>
+<br/>
+时间：` + login.CreatedAt.Format("2006-01-02 15:04:05") + `<br/>
+IP 地址：` + html.EscapeString(login.IP) + `<br/>
+登录地址：` + html.EscapeString(login.Address) + `<br/>
+操作系统：` + html.EscapeString(login.OS) + `<br/>
+设备：` + html.EscapeString(login.DeviceInfo) + `<br/>
+浏览器：` + html.EscapeString(login.BrowserInfo) + `<br/>
+<br/>
+如果这是您本人的操作，请忽略此邮件。<br/>
+<br/>
+如果这不是您本人的操作，请点击下面的链接，我们会下线您在所有设备上的登录、撤销所有个人访问令牌，并要求您重置密码：<br/>
+<br/>
+<a href="` + html.EscapeString(link) + `">这不是我本人的操作</a><br/>
+<br/>
+该链接在 ` + cfg.LinkExpiry + ` 内有效。<br/>
+<br/>
+祝好，<br/>` +
+		global.Config.Website.Title + `<br/>
+<br/>`
+	return utils.Email(user.Email, subject, body)
+}
+
+// LoginAlertReject 用户确认异常登录不是本人操作，下线所有会话并要求重置密码，返回用于重置密码的令牌
+func (userService *UserService) LoginAlertReject(token string) (string, error) {
+	key := loginAlertKey(utils.SHA256Hex(token))
+	userIDStr, err := global.Redis.Get(key).Result()
+	if err != nil {
+		return "", errors.New("the link is invalid or has expired")
+	}
+	// 删除成功的请求才能继续，保证链接只能使用一次
+	if deleted, err := global.Redis.Del(key).Result(); err != nil || deleted == 0 {
+		return "", errors.New("the link is invalid or has expired")
+	}
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return "", err
+	}
+
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return "", err
+	}
+	if err := global.DB.Model(&user).Update("password_reset_required", true).Error; err != nil {
+		return "", err
+	}
+	if err := ServiceGroupApp.SessionService.SessionRevokeAll(user.ID); err != nil {
+		return "", err
+	}
+	if err := ServiceGroupApp.PersonalAccessTokenService.TokenRevokeAll(user.ID); err != nil {
+		return "", err
+	}
+	global.Log.Warn("User reported an unrecognized login", zap.Uint("user_id", user.ID))
+	return userService.createPasswordResetToken(user.ID)
+}
+
+// loginAlertKey 异常登录提醒链接的令牌在 Redis 中的键
+func loginAlertKey(hash string) string {
+	return "login_alert:" + hash
+}
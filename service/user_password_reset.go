@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"server/global"
+	"server/model/database"
+	"server/utils"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+// passwordResetTTL 密码重置链接的有效期
+const passwordResetTTL = 30 * time.Minute
+
+// PasswordResetRequest 向邮箱发送密码重置链接，邮箱未注册时不做任何处理，避免泄露邮箱是否已注册
+func (userService *UserService) PasswordResetRequest(email string) error {
+	var user database.User
+	if err := global.DB.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil
+	}
+
+	token, err := userService.createPasswordResetToken(user.ID)
+	if err != nil {
+		return err
+	}
+
+	link := utils.SiteURL("reset-password", url.Values{"token": {token}})
+	subject := "重置您的密码"
+	body := `亲爱的用户[` + user.Username + `]，<br/>
+<br/>
+我们收到了重置您在` + global.Config.Website.Title + `的账户密码的请求，请点击下面的链接设置新密码：<br/>
+<br/>
+<a href="` + link + `">` + link + `</a><br/>
+<br/>
+该链接在 30 分钟内有效，且只能使用一次。重置成功后，您在所有设备上的登录都会失效。<br/>
+<br/>
+如果您没有请求重置密码，请忽略此邮件，您的密码不会被修改。<br/>
+<br/>
+祝好，<br/>` +
+		global.Config.Website.Title + `<br/>
+<br/>`
+
+	go func() {
+		if err := utils.Email(user.Email, subject, body); err != nil {
+			global.Log.Error("Failed to send password reset email", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// createPasswordResetToken 为用户生成密码重置令牌，每个用户只保留最新的一条
+func (userService *UserService) createPasswordResetToken(userID uint) (string, error) {
+	token, err := utils.GenerateRandomKey(32)
+	if err != nil {
+		return "", err
+	}
+	hash := utils.SHA256Hex(token)
+
+	userKey := passwordResetUserKey(userID)
+	if oldHash, err := global.Redis.Get(userKey).Result(); err == nil {
+		global.Redis.Del(passwordResetKey(oldHash))
+	}
+	if err := global.Redis.Set(passwordResetKey(hash), strconv.FormatUint(uint64(userID), 10), passwordResetTTL).Err(); err != nil {
+		return "", err
+	}
+	if err := global.Redis.Set(userKey, hash, passwordResetTTL).Err(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// PasswordReset 使用重置链接中的令牌设置新密码，令牌只能使用一次，成功后下线该用户的所有会话
+func (userService *UserService) PasswordReset(token, newPassword string) error {
+	hash := utils.SHA256Hex(token)
+	key := passwordResetKey(hash)
+	userIDStr, err := global.Redis.Get(key).Result()
+	if errors.Is(err, redis.Nil) {
+		return errors.New("the reset link is invalid or has expired")
+	}
+	if err != nil {
+		return err
+	}
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return err
+	}
+	if err := utils.ValidatePassword(newPassword, user.Email, user.Username); err != nil {
+		return err
+	}
+
+	// 删除成功的请求才能继续，保证令牌只能使用一次
+	deleted, err := global.Redis.Del(key).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("the reset link is invalid or has expired")
+	}
+	global.Redis.Del(passwordResetUserKey(user.ID))
+
+	if err := global.DB.Model(&user).Update("password", utils.BcryptHash(newPassword)).Error; err != nil {
+		return err
+	}
+	return userService.afterPasswordReset(user)
+}
+
+// afterPasswordReset 重置密码后下线该用户的所有会话并撤销所有个人访问令牌，解除登录锁定，并清除强制重置密码的标记
+func (userService *UserService) afterPasswordReset(user database.User) error {
+	if err := userService.UserUnlock(user.ID); err != nil {
+		global.Log.Error("Failed to unlock user after password reset", zap.Error(err))
+	}
+	if user.PasswordResetRequired {
+		if err := global.DB.Model(&user).Update("password_reset_required", false).Error; err != nil {
+			return err
+		}
+	}
+	if err := ServiceGroupApp.PersonalAccessTokenService.TokenRevokeAll(user.ID); err != nil {
+		return err
+	}
+	return ServiceGroupApp.SessionService.SessionRevokeAll(user.ID)
+}
+
+// passwordResetKey 密码重置令牌在 Redis 中的键
+func passwordResetKey(hash string) string {
+	return "password_reset:" + hash
+}
+
+// passwordResetUserKey 用户最新的密码重置令牌在 Redis 中的键
+func passwordResetUserKey(userID uint) string {
+	return "password_reset_user:" + strconv.FormatUint(uint64(userID), 10)
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"server/global"
+	"server/model/database"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	twoFactorPendingTTL    = 5 * time.Minute // 两步验证待验证令牌的有效期
+	twoFactorMaxAttempts   = 5               // 每个待验证令牌允许的最大尝试次数
+	twoFactorRecoveryCount = 10              // 每次生成的恢复码数量
+)
+
+// TwoFactorEnabled 判断用户是否已启用两步验证
+func (userService *UserService) TwoFactorEnabled(userID uint) bool {
+	var count int64
+	global.DB.Model(&database.UserTwoFactor{}).Where("user_id = ? AND enabled = ?", userID, true).Count(&count)
+	return count > 0
+}
+
+// TwoFactorEnroll 为用户生成新的 TOTP 密钥，需要调用 TwoFactorConfirm 确认后才会启用
+func (userService *UserService) TwoFactorEnroll(userID uint) (response.TwoFactorEnroll, error) {
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return response.TwoFactorEnroll{}, err
+	}
+
+	var twoFactor database.UserTwoFactor
+	err := global.DB.Where("user_id = ?", userID).First(&twoFactor).Error
+	if err == nil && twoFactor.Enabled {
+		return response.TwoFactorEnroll{}, errors.New("two-factor authentication has already been enabled")
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.TwoFactorEnroll{}, err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return response.TwoFactorEnroll{}, err
+	}
+	twoFactor.UserID = userID
+	twoFactor.Secret = secret
+	twoFactor.LastUsedStep = 0
+	if err := global.DB.Save(&twoFactor).Error; err != nil {
+		return response.TwoFactorEnroll{}, err
+	}
+
+	account := user.Email
+	if account == "" {
+		account = user.Username
+	}
+	return response.TwoFactorEnroll{
+		Secret: secret,
+		URI:    utils.TOTPProvisioningURI(global.Config.Website.Title, account, secret),
+	}, nil
+}
+
+// TwoFactorConfirm 使用动态码确认启用两步验证，并返回一组只展示一次的恢复码
+func (userService *UserService) TwoFactorConfirm(userID uint, code string) ([]string, error) {
+	var twoFactor database.UserTwoFactor
+	if err := global.DB.Where("user_id = ?", userID).First(&twoFactor).Error; err != nil {
+		return nil, errors.New("please enroll two-factor authentication first")
+	}
+	if twoFactor.Enabled {
+		return nil, errors.New("two-factor authentication has already been enabled")
+	}
+
+	step, ok := utils.ValidateTOTP(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, errors.New("invalid two-factor authentication code")
+	}
+
+	var codes []string
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&twoFactor).Updates(map[string]interface{}{"enabled": true, "last_used_step": step}).Error; err != nil {
+			return err
+		}
+		var err error
+		codes, err = userService.generateRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// TwoFactorDisable 在校验密码后关闭两步验证，没有密码的账户改为校验动态码或恢复码
+func (userService *UserService) TwoFactorDisable(userID uint, req request.TwoFactorDisable) error {
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return err
+	}
+	if global.Config.System.RequireAdmin2FA && userService.isStaff(userID) {
+		return errors.New("two-factor authentication is required for administrators")
+	}
+
+	if user.Password != "" {
+		if !utils.BcryptCheck(req.Password, user.Password) {
+			return errors.New("invalid password")
+		}
+	} else {
+		if req.Code == "" {
+			return errors.New("please enter a two-factor authentication code or a recovery code")
+		}
+		var twoFactor database.UserTwoFactor
+		if err := global.DB.Where("user_id = ? AND enabled = ?", userID, true).First(&twoFactor).Error; err != nil {
+			return errors.New("two-factor authentication is not enabled")
+		}
+		if err := userService.verifyTwoFactorCode(twoFactor, req.Code); err != nil {
+			return err
+		}
+	}
+
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&database.UserTwoFactor{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ?", userID).Delete(&database.RecoveryCode{}).Error
+	})
+}
+
+// TwoFactorPending 为已通过密码校验、尚未完成两步验证的用户生成短期的待验证令牌
+func (userService *UserService) TwoFactorPending(userID uint) (response.TwoFactorPending, error) {
+	token, err := utils.GenerateRandomKey(32)
+	if err != nil {
+		return response.TwoFactorPending{}, err
+	}
+	if err := global.Redis.Set(twoFactorPendingKey(token), userID, twoFactorPendingTTL).Err(); err != nil {
+		return response.TwoFactorPending{}, err
+	}
+	return response.TwoFactorPending{
+		PendingToken: token,
+		ExpiresAt:    time.Now().Add(twoFactorPendingTTL).UnixMilli(),
+	}, nil
+}
+
+// TwoFactorVerify 校验待验证令牌和动态码（或恢复码），成功后返回对应的用户
+func (userService *UserService) TwoFactorVerify(pendingToken, code string) (database.User, error) {
+	key := twoFactorPendingKey(pendingToken)
+	userIDStr, err := global.Redis.Get(key).Result()
+	if err != nil {
+		return database.User{}, errors.New("the login has expired, please log in again")
+	}
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return database.User{}, err
+	}
+
+	// 限制尝试次数，超出后令牌作废，需要重新登录
+	attemptsKey := key + ":attempts"
+	attempts, err := global.Redis.Incr(attemptsKey).Result()
+	if err != nil {
+		return database.User{}, err
+	}
+	global.Redis.Expire(attemptsKey, twoFactorPendingTTL)
+	if attempts > twoFactorMaxAttempts {
+		global.Redis.Del(key, attemptsKey)
+		return database.User{}, errors.New("too many attempts, please log in again")
+	}
+
+	var twoFactor database.UserTwoFactor
+	if err := global.DB.Where("user_id = ? AND enabled = ?", userID, true).First(&twoFactor).Error; err != nil {
+		return database.User{}, err
+	}
+	if err := userService.verifyTwoFactorCode(twoFactor, code); err != nil {
+		return database.User{}, err
+	}
+	global.Redis.Del(key, attemptsKey)
+
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return database.User{}, err
+	}
+	return user, nil
+}
+
+// verifyTwoFactorCode 校验动态码或恢复码，恢复码使用后立即作废
+func (userService *UserService) verifyTwoFactorCode(twoFactor database.UserTwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if step, ok := utils.ValidateTOTP(twoFactor.Secret, code, time.Now()); ok {
+		if step <= twoFactor.LastUsedStep {
+			return errors.New("this two-factor authentication code has already been used")
+		}
+		// 条件更新，防止并发请求重复使用同一动态码
+		result := global.DB.Model(&database.UserTwoFactor{}).
+			Where("id = ? AND last_used_step < ?", twoFactor.ID, step).
+			Update("last_used_step", step)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("this two-factor authentication code has already been used")
+		}
+		return nil
+	}
+
+	result := global.DB.Model(&database.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used = ?", twoFactor.UserID, hashRecoveryCode(code), false).
+		Update("used", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("invalid two-factor authentication code")
+	}
+	return nil
+}
+
+// generateRecoveryCodes 删除用户旧的恢复码并生成一组新的恢复码，数据库中只保存摘要
+func (userService *UserService) generateRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&database.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, twoFactorRecoveryCount)
+	records := make([]database.RecoveryCode, 0, twoFactorRecoveryCount)
+	for i := 0; i < twoFactorRecoveryCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := base32.StdEncoding.EncodeToString(b)
+		code = code[:4] + "-" + code[4:]
+		codes = append(codes, code)
+		records = append(records, database.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode 计算恢复码的摘要，忽略大小写和分隔符
+func hashRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return utils.SHA256Hex(code)
+}
+
+// twoFactorPendingKey 两步验证待验证令牌在 Redis 中的键
+func twoFactorPendingKey(token string) string {
+	return "two_factor_pending:" + utils.SHA256Hex(token)
+}
+
+// isStaff 判断用户是否拥有管理权限，开启强制两步验证时这些用户不能关闭两步验证
+func (userService *UserService) isStaff(userID uint) bool {
+	permissions, err := ServiceGroupApp.RBACService.UserPermissions(userID)
+	return err != nil || len(permissions) > 0
+}
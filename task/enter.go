@@ -31,5 +31,61 @@ func RegisterScheduledTasks(c *cron.Cron) error {
 	}); err != nil {
 		return err
 	}
+
+	if _, err := c.AddFunc("@daily", func() {
+		if err := ReconcileArticleCountersTask(); err != nil {
+			global.Log.Error("Failed to reconcile article counters:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := c.AddFunc("@daily", func() {
+		if err := DeleteScheduledUsersTask(); err != nil {
+			global.Log.Error("Failed to delete scheduled users:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := c.AddFunc("@hourly", func() {
+		if err := ClearExpiredUserExportsTask(); err != nil {
+			global.Log.Error("Failed to clear expired user exports:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := c.AddFunc("@daily", func() {
+		if err := ClearExpiredSessionsTask(); err != nil {
+			global.Log.Error("Failed to clear expired sessions:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := c.AddFunc("@hourly", func() {
+		if err := RotateJwtKeysTask(); err != nil {
+			global.Log.Error("Failed to rotate JWT keys:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := c.AddFunc("@daily", func() {
+		if err := ClearExpiredJwtBlacklistTask(); err != nil {
+			global.Log.Error("Failed to clear expired JWT blacklist:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := c.AddFunc("@every 1m", func() {
+		if err := FlushReadingHistoryTask(); err != nil {
+			global.Log.Error("Failed to flush reading history:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
 	return nil
 }
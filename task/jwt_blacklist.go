@@ -0,0 +1,8 @@
+package task
+
+import "server/service"
+
+// ClearExpiredJwtBlacklistTask 清理已经过期的 JWT 黑名单记录
+func ClearExpiredJwtBlacklistTask() error {
+	return service.ServiceGroupApp.JwtService.ClearExpiredBlacklist()
+}
@@ -0,0 +1,16 @@
+package task
+
+import (
+	"errors"
+	"server/service"
+)
+
+// RotateJwtKeysTask 按照轮换周期轮换 JWT 签名密钥，并清理已过期的密钥
+func RotateJwtKeysTask() error {
+	jwtKeyService := service.ServiceGroupApp.JwtKeyService
+	// 其他实例正在轮换时由该实例完成
+	if err := jwtKeyService.RotateJwtKeysIfDue(); err != nil && !errors.Is(err, service.ErrJwtKeyRotationInProgress) {
+		return err
+	}
+	return jwtKeyService.ClearExpiredJwtKeys()
+}
@@ -0,0 +1,8 @@
+package task
+
+import "server/service"
+
+// FlushReadingHistoryTask 把 Redis 中缓冲的阅读上报写入 MySQL
+func FlushReadingHistoryTask() error {
+	return service.ServiceGroupApp.ArticleService.FlushReadingHistory()
+}
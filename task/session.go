@@ -0,0 +1,8 @@
+package task
+
+import "server/service"
+
+// ClearExpiredSessionsTask 清理已经过期的登录会话
+func ClearExpiredSessionsTask() error {
+	return service.ServiceGroupApp.SessionService.ClearExpiredSessions()
+}
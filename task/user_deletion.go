@@ -0,0 +1,8 @@
+package task
+
+import "server/service"
+
+// DeleteScheduledUsersTask 删除注销冷静期已结束的账户
+func DeleteScheduledUsersTask() error {
+	return service.ServiceGroupApp.UserService.DeleteScheduledUsers()
+}
@@ -0,0 +1,8 @@
+package task
+
+import "server/service"
+
+// ClearExpiredUserExportsTask 删除下载链接已过期的个人数据导出文件
+func ClearExpiredUserExportsTask() error {
+	return service.ServiceGroupApp.UserService.ClearExpiredUserExports()
+}
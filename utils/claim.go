@@ -8,6 +8,7 @@ import (
 	"server/global"
 	"server/model/appTypes"
 	"server/model/request"
+	"strings"
 )
 
 // SetRefreshToken 设置Refresh Token的cookie
@@ -51,6 +52,15 @@ func GetAccessToken(c *gin.Context) string {
 	return token
 }
 
+// GetBearerToken 从Authorization请求头获取Bearer Token
+func GetBearerToken(c *gin.Context) string {
+	header := c.Request.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
 // GetRefreshToken 从cookie获取Refresh Token
 func GetRefreshToken(c *gin.Context) string {
 	// 尝试从cookie中获取refresh-token
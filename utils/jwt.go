@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"github.com/gofrs/uuid"
 	"github.com/golang-jwt/jwt/v4"
 	"server/global"
 	"server/model/request"
@@ -9,8 +10,8 @@ import (
 )
 
 type JWT struct {
-	AccessTokenSecret  []byte // Access Token 的密钥
-	RefreshTokenSecret []byte // Refresh Token 的密钥
+	AccessTokenSecret  []byte // Access Token 的密钥，用于签发和校验不含 kid 的旧版本令牌
+	RefreshTokenSecret []byte // Refresh Token 的密钥，用于签发和校验不含 kid 的旧版本令牌
 }
 
 var (
@@ -44,8 +45,7 @@ func (j *JWT) CreateAccessClaims(baseClaims request.BaseClaims) request.JwtCusto
 
 // CreateAccessToken 创建 Access Token，通过 Claims 生成 JWT Token
 func (j *JWT) CreateAccessToken(claims request.JwtCustomClaims) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims) // 创建新的 JWT Token
-	return token.SignedString(j.AccessTokenSecret)             // 使用 AccessToken 密钥签名并返回 Token 字符串
+	return j.signToken(claims, JwtKeyUseAccess, j.AccessTokenSecret)
 }
 
 // CreateRefreshClaims 创建 Refresh Token 的 Claims，包含用户信息和过期时间等
@@ -54,6 +54,7 @@ func (j *JWT) CreateRefreshClaims(baseClaims request.BaseClaims) request.JwtCust
 	claims := request.JwtCustomRefreshClaims{
 		UserID: baseClaims.UserID, // 用户 ID
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.Must(uuid.NewV4()).String(),       // 唯一标识，对应一个登录会话
 			Audience:  jwt.ClaimStrings{"TAP"},                // 受众
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ep)), // 过期时间
 			Issuer:    global.Config.Jwt.Issuer,               // 签名的发行者
@@ -64,13 +65,12 @@ func (j *JWT) CreateRefreshClaims(baseClaims request.BaseClaims) request.JwtCust
 
 // CreateRefreshToken 创建 Refresh Token，通过 Claims 生成 JWT Token
 func (j *JWT) CreateRefreshToken(claims request.JwtCustomRefreshClaims) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims) // 创建新的 JWT Token
-	return token.SignedString(j.RefreshTokenSecret)            // 使用 RefreshToken 密钥签名并返回 Token 字符串
+	return j.signToken(claims, JwtKeyUseRefresh, j.RefreshTokenSecret)
 }
 
 // ParseAccessToken 解析 Access Token，验证 Token 并返回 Claims 信息
 func (j *JWT) ParseAccessToken(tokenString string) (*request.JwtCustomClaims, error) {
-	claims, err := j.parseToken(tokenString, &request.JwtCustomClaims{}, j.AccessTokenSecret) // 解析 Token
+	claims, err := j.parseToken(tokenString, &request.JwtCustomClaims{}, JwtKeyUseAccess, j.AccessTokenSecret) // 解析 Token
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +82,7 @@ func (j *JWT) ParseAccessToken(tokenString string) (*request.JwtCustomClaims, er
 
 // ParseRefreshToken 解析 Refresh Token，验证 Token 并返回 Claims 信息
 func (j *JWT) ParseRefreshToken(tokenString string) (*request.JwtCustomRefreshClaims, error) {
-	claims, err := j.parseToken(tokenString, &request.JwtCustomRefreshClaims{}, j.RefreshTokenSecret) // 解析 Token
+	claims, err := j.parseToken(tokenString, &request.JwtCustomRefreshClaims{}, JwtKeyUseRefresh, j.RefreshTokenSecret) // 解析 Token
 	if err != nil {
 		return nil, err
 	}
@@ -92,10 +92,34 @@ func (j *JWT) ParseRefreshToken(tokenString string) (*request.JwtCustomRefreshCl
 	return nil, TokenInvalid // 如果解析结果无效，返回 TokenInvalid 错误
 }
 
+// signToken 使用指定用途当前的签名密钥签发 Token，尚未加载签名密钥时使用配置中的密钥
+func (j *JWT) signToken(claims jwt.Claims, use string, legacySecret []byte) (string, error) {
+	key := signingJwtKey(use)
+	if key == nil || key.signKey == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims) // 创建新的 JWT Token
+		return token.SignedString(legacySecret)
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid // 写入密钥 ID，校验时据此查找密钥
+	return token.SignedString(key.signKey)
+}
+
 // parseToken 通用的 Token 解析方法，验证 Token 是否有效并返回 Claims
-func (j *JWT) parseToken(tokenString string, claims jwt.Claims, secretKey interface{}) (interface{}, error) {
+func (j *JWT) parseToken(tokenString string, claims jwt.Claims, use string, legacySecret []byte) (interface{}, error) {
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return secretKey, nil // 返回密钥以验证 Token
+		// 不含 kid 的旧版本令牌使用配置中的密钥校验
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			if token.Method != jwt.SigningMethodHS256 {
+				return nil, TokenInvalid
+			}
+			return legacySecret, nil
+		}
+		key := lookupJwtKey(kid, use)
+		if key == nil || token.Method.Alg() != key.method.Alg() {
+			return nil, TokenInvalid
+		}
+		return key.verifyKey, nil // 返回密钥以验证 Token
 	})
 	if err != nil {
 		if ve, ok := err.(*jwt.ValidationError); ok { // 处理 Token 验证错误
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"server/model/database"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// JWT 密钥用途
+const (
+	JwtKeyUseAccess  = "access"
+	JwtKeyUseRefresh = "refresh"
+)
+
+// minHmacSecretSize HS256 密钥的最小长度，过短或为空的密钥会导致任何人都能伪造令牌
+const minHmacSecretSize = 32
+
+// jwtKey 解析后的 JWT 密钥
+type jwtKey struct {
+	kid       string
+	use       string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	publicKey crypto.PublicKey
+}
+
+// jwtKeyring 当前进程中可用的 JWT 密钥
+var jwtKeyring = struct {
+	sync.RWMutex
+	keys    map[string]*jwtKey
+	signing map[string]*jwtKey
+}{}
+
+// GenerateJwtKey 按照指定算法生成新的密钥
+func GenerateJwtKey(use, algorithm string) (database.JwtKey, error) {
+	kid, err := GenerateRandomKey(12)
+	if err != nil {
+		return database.JwtKey{}, err
+	}
+	key := database.JwtKey{Kid: kid, Use: use, Algorithm: algorithm}
+
+	var private, public interface{}
+	switch algorithm {
+	case "HS256":
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return database.JwtKey{}, err
+		}
+		key.PrivateKey = base64.StdEncoding.EncodeToString(secret)
+		return key, nil
+	case "RS256":
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return database.JwtKey{}, err
+		}
+		private, public = rsaKey, &rsaKey.PublicKey
+	case "EdDSA":
+		edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return database.JwtKey{}, err
+		}
+		private, public = edPrivate, edPublic
+	default:
+		return database.JwtKey{}, fmt.Errorf("unsupported jwt algorithm: %s", algorithm)
+	}
+
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return database.JwtKey{}, err
+	}
+	publicBytes, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return database.JwtKey{}, err
+	}
+	key.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}))
+	key.PublicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}))
+	return key, nil
+}
+
+// SetJwtKeys 替换当前进程中可用的密钥，每种用途中未停止签发且最新的密钥用于签发
+func SetJwtKeys(keys []database.JwtKey) error {
+	parsed := make(map[string]*jwtKey, len(keys))
+	signing := make(map[string]*jwtKey)
+	signingID := make(map[string]uint)
+	for _, key := range keys {
+		k, err := parseJwtKey(key)
+		if err != nil {
+			// 已停止签发的密钥无法解析时不再用于校验，签发密钥必须可用
+			if key.RetiredAt != nil {
+				continue
+			}
+			return fmt.Errorf("failed to parse jwt key %s: %w", key.Kid, err)
+		}
+		parsed[key.Kid] = k
+		if key.RetiredAt == nil && key.ID >= signingID[key.Use] {
+			signing[key.Use] = k
+			signingID[key.Use] = key.ID
+		}
+	}
+
+	jwtKeyring.Lock()
+	defer jwtKeyring.Unlock()
+	jwtKeyring.keys = parsed
+	jwtKeyring.signing = signing
+	return nil
+}
+
+// parseJwtKey 解析数据库中保存的密钥
+func parseJwtKey(key database.JwtKey) (*jwtKey, error) {
+	k := &jwtKey{kid: key.Kid, use: key.Use}
+	switch key.Algorithm {
+	case "HS256":
+		secret, err := base64.StdEncoding.DecodeString(key.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(secret) < minHmacSecretSize {
+			return nil, errors.New("hmac secret is too short")
+		}
+		k.method, k.signKey, k.verifyKey = jwt.SigningMethodHS256, secret, secret
+		return k, nil
+	case "RS256":
+		k.method = jwt.SigningMethodRS256
+	case "EdDSA":
+		k.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm: %s", key.Algorithm)
+	}
+
+	block, _ := pem.Decode([]byte(key.PublicKey))
+	if block == nil {
+		return nil, errors.New("invalid public key")
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	k.verifyKey, k.publicKey = public, public
+
+	// 私钥只在签发时使用，已停止签发的密钥可以没有私钥
+	if block, _ := pem.Decode([]byte(key.PrivateKey)); block != nil {
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		k.signKey = private
+	}
+	return k, nil
+}
+
+// signingJwtKey 获取指定用途当前用于签发的密钥，尚未加载密钥时返回 nil
+func signingJwtKey(use string) *jwtKey {
+	jwtKeyring.RLock()
+	defer jwtKeyring.RUnlock()
+	return jwtKeyring.signing[use]
+}
+
+// lookupJwtKey 根据 kid 查找指定用途的密钥
+func lookupJwtKey(kid, use string) *jwtKey {
+	jwtKeyring.RLock()
+	defer jwtKeyring.RUnlock()
+	k, ok := jwtKeyring.keys[kid]
+	if !ok || k.use != use {
+		return nil
+	}
+	return k
+}
+
+// JWKS 返回所有可用于校验 Access Token 的非对称公钥，格式为 JSON Web Key Set
+func JWKS() map[string]interface{} {
+	jwtKeyring.RLock()
+	defer jwtKeyring.RUnlock()
+
+	keys := []map[string]string{}
+	for _, k := range jwtKeyring.keys {
+		if k.use != JwtKeyUseAccess || k.publicKey == nil {
+			continue
+		}
+		jwk := map[string]string{
+			"kid": k.kid,
+			"use": "sig",
+			"alg": k.method.Alg(),
+		}
+		switch public := k.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk["kty"] = "RSA"
+			jwk["n"] = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk["kty"] = "OKP"
+			jwk["crv"] = "Ed25519"
+			jwk["x"] = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		keys = append(keys, jwk)
+	}
+	return map[string]interface{}{"keys": keys}
+}
@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"server/model/database"
+)
+
+func mustGenerateJwtKey(t *testing.T, id uint, use, algorithm string) database.JwtKey {
+	t.Helper()
+	key, err := GenerateJwtKey(use, algorithm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.ID = id
+	return key
+}
+
+// retire 模拟 RotateJwtKeys 停止签发一个密钥，wipe 表示是否删除私钥
+func retire(key database.JwtKey, wipe bool) database.JwtKey {
+	now := time.Now()
+	key.RetiredAt = &now
+	if wipe {
+		key.PrivateKey = ""
+	}
+	return key
+}
+
+func TestParseJwtKey(t *testing.T) {
+	hs := mustGenerateJwtKey(t, 1, JwtKeyUseAccess, "HS256")
+	rs := mustGenerateJwtKey(t, 2, JwtKeyUseAccess, "RS256")
+	ed := mustGenerateJwtKey(t, 3, JwtKeyUseAccess, "EdDSA")
+
+	withPrivate := func(key database.JwtKey, private string) database.JwtKey {
+		key.PrivateKey = private
+		return key
+	}
+	withPublic := func(key database.JwtKey, public string) database.JwtKey {
+		key.PublicKey = public
+		return key
+	}
+	withAlgorithm := func(key database.JwtKey, algorithm string) database.JwtKey {
+		key.Algorithm = algorithm
+		return key
+	}
+
+	tests := []struct {
+		name     string
+		key      database.JwtKey
+		wantErr  bool
+		wantSign bool
+	}{
+		{"HS256", hs, false, true},
+		{"HS256 empty secret", withPrivate(hs, ""), true, false},
+		{"HS256 short secret", withPrivate(hs, base64.StdEncoding.EncodeToString(make([]byte, minHmacSecretSize-1))), true, false},
+		{"HS256 invalid base64", withPrivate(hs, "not base64!"), true, false},
+		{"RS256", rs, false, true},
+		{"RS256 without private key", withPrivate(rs, ""), false, false},
+		{"RS256 invalid public key", withPublic(rs, "invalid"), true, false},
+		{"EdDSA", ed, false, true},
+		{"EdDSA without private key", withPrivate(ed, ""), false, false},
+		{"unknown algorithm", withAlgorithm(hs, "none"), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := parseJwtKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJwtKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if k.verifyKey == nil {
+				t.Errorf("parseJwtKey() returned no verify key")
+			}
+			if (k.signKey != nil) != tt.wantSign {
+				t.Errorf("parseJwtKey() sign key = %v, want sign key %v", k.signKey != nil, tt.wantSign)
+			}
+		})
+	}
+}
+
+func TestSetJwtKeysSigningKey(t *testing.T) {
+	oldAccess := mustGenerateJwtKey(t, 1, JwtKeyUseAccess, "HS256")
+	access := mustGenerateJwtKey(t, 3, JwtKeyUseAccess, "EdDSA")
+	refresh := mustGenerateJwtKey(t, 2, JwtKeyUseRefresh, "HS256")
+	retiredAccess := retire(mustGenerateJwtKey(t, 4, JwtKeyUseAccess, "HS256"), false)
+
+	tests := []struct {
+		name        string
+		keys        []database.JwtKey
+		wantErr     bool
+		wantAccess  string
+		wantRefresh string
+	}{
+		{"newest key signs", []database.JwtKey{oldAccess, refresh, access}, false, access.Kid, refresh.Kid},
+		{"order does not matter", []database.JwtKey{access, refresh, oldAccess}, false, access.Kid, refresh.Kid},
+		{"retired key never signs", []database.JwtKey{oldAccess, retiredAccess}, false, oldAccess.Kid, ""},
+		{"unparsable retired key is skipped", []database.JwtKey{access, retire(oldAccess, true)}, false, access.Kid, ""},
+		{"unparsable signing key fails", []database.JwtKey{access, func() database.JwtKey { k := refresh; k.PrivateKey = ""; return k }()}, true, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SetJwtKeys(tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetJwtKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for use, want := range map[string]string{JwtKeyUseAccess: tt.wantAccess, JwtKeyUseRefresh: tt.wantRefresh} {
+				got := ""
+				if k := signingJwtKey(use); k != nil {
+					got = k.kid
+				}
+				if got != want {
+					t.Errorf("signingJwtKey(%s) = %q, want %q", use, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestJwtKeyRotation(t *testing.T) {
+	j := &JWT{}
+	claims := func() jwt.Claims {
+		return jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+	}
+	parse := func(token, use string) error {
+		_, err := j.parseToken(token, &jwt.RegisteredClaims{}, use, nil)
+		return err
+	}
+	forge := func(kid string, method jwt.SigningMethod, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims())
+		token.Header["kid"] = kid
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	for _, algorithm := range []string{"HS256", "RS256", "EdDSA"} {
+		t.Run(algorithm, func(t *testing.T) {
+			old := mustGenerateJwtKey(t, 1, JwtKeyUseAccess, algorithm)
+			if err := SetJwtKeys([]database.JwtKey{old}); err != nil {
+				t.Fatal(err)
+			}
+			oldToken, err := j.signToken(claims(), JwtKeyUseAccess, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 轮换后旧密钥停止签发，HS256 的密钥同时用于校验，必须保留
+			current := mustGenerateJwtKey(t, 2, JwtKeyUseAccess, algorithm)
+			if err := SetJwtKeys([]database.JwtKey{retire(old, algorithm != "HS256"), current}); err != nil {
+				t.Fatal(err)
+			}
+			newToken, err := j.signToken(claims(), JwtKeyUseAccess, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if kid := signingJwtKey(JwtKeyUseAccess).kid; kid != current.Kid {
+				t.Fatalf("signing kid = %s, want %s", kid, current.Kid)
+			}
+
+			tests := []struct {
+				name    string
+				token   string
+				use     string
+				wantErr bool
+			}{
+				{"token signed before rotation", oldToken, JwtKeyUseAccess, false},
+				{"token signed after rotation", newToken, JwtKeyUseAccess, false},
+				{"wrong use", newToken, JwtKeyUseRefresh, true},
+				{"empty HMAC secret with retired kid", forge(old.Kid, jwt.SigningMethodHS256, []byte{}), JwtKeyUseAccess, true},
+				{"empty HMAC secret with current kid", forge(current.Kid, jwt.SigningMethodHS256, []byte{}), JwtKeyUseAccess, true},
+				{"public key as HMAC secret", forge(current.Kid, jwt.SigningMethodHS256, []byte(current.PublicKey)), JwtKeyUseAccess, true},
+				{"unknown kid", forge("unknown", jwt.SigningMethodHS256, []byte("secret")), JwtKeyUseAccess, true},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					if err := parse(tt.token, tt.use); (err != nil) != tt.wantErr {
+						t.Errorf("parseToken() error = %v, wantErr %v", err, tt.wantErr)
+					}
+				})
+			}
+		})
+	}
+
+	// 旧版本的轮换删除了 HS256 的密钥，这样的密钥不能被加载，用空密钥伪造的令牌必须被拒绝
+	t.Run("HS256 key wiped by an earlier rotation", func(t *testing.T) {
+		wiped := retire(mustGenerateJwtKey(t, 1, JwtKeyUseAccess, "HS256"), true)
+		current := mustGenerateJwtKey(t, 2, JwtKeyUseAccess, "HS256")
+		if err := SetJwtKeys([]database.JwtKey{wiped, current}); err != nil {
+			t.Fatal(err)
+		}
+		if err := parse(forge(wiped.Kid, jwt.SigningMethodHS256, []byte{}), JwtKeyUseAccess); err == nil {
+			t.Errorf("parseToken() accepted a token forged with an empty HMAC secret")
+		}
+	})
+}
@@ -57,3 +57,17 @@ func ParseDuration(d string) (time.Duration, error) {
 	// 返回总的持续时间
 	return totalDuration, nil
 }
+
+// ParseRetryDelays 解析事件处理失败后的重试间隔，格式与 ParseDuration 相同，跳过无效的值，未配置时使用默认值
+func ParseRetryDelays(retryDelays []string) []time.Duration {
+	if len(retryDelays) == 0 {
+		return []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute, 10 * time.Minute}
+	}
+	delays := make([]time.Duration, 0, len(retryDelays))
+	for _, s := range retryDelays {
+		if d, err := ParseDuration(s); err == nil && d > 0 {
+			delays = append(delays, d)
+		}
+	}
+	return delays
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"server/global"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ValidatePassword 按照配置的密码策略校验密码，personal 为用户的邮箱、用户名等个人信息
+func ValidatePassword(password string, personal ...string) error {
+	policy := global.Config.Security.PasswordPolicy
+
+	length := utf8.RuneCountInString(password)
+	if length < policy.MinLength {
+		return fmt.Errorf("the password must be at least %d characters", policy.MinLength)
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		return fmt.Errorf("the password must be at most %d characters", policy.MaxLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+	if policy.RequireUpper && !hasUpper {
+		return errors.New("the password must contain an uppercase letter")
+	}
+	if policy.RequireLower && !hasLower {
+		return errors.New("the password must contain a lowercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		return errors.New("the password must contain a digit")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		return errors.New("the password must contain a special character")
+	}
+
+	if policy.RejectPersonalInfo {
+		lower := strings.ToLower(password)
+		for _, info := range personal {
+			info = strings.ToLower(strings.TrimSpace(info))
+			// 邮箱同时检查 @ 之前的部分
+			if i := strings.Index(info, "@"); i > 0 && len(info[:i]) >= 3 && strings.Contains(lower, info[:i]) {
+				return errors.New("the password must not contain your email or username")
+			}
+			if len(info) >= 3 && strings.Contains(lower, info) {
+				return errors.New("the password must not contain your email or username")
+			}
+		}
+	}
+	return nil
+}
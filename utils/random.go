@@ -1,14 +1,17 @@
 package utils
 
 import (
+	"crypto/rand"
 	"fmt"
 	"math"
-	"math/rand"
-	"time"
+	"math/big"
 )
 
 // GenerateVerificationCode 生成一个指定长度的随机验证码
 func GenerateVerificationCode(length int) string {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	return fmt.Sprintf("%0*d", length, r.Intn(int(math.Pow10(length))))
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(math.Pow10(length))))
+	if err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%0*d", length, n.Int64())
 }
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret RFC 4226 和 RFC 6238 测试向量使用的 SHA1 密钥 "12345678901234567890"
+var rfcSecret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226 附录 D 的测试向量
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		if got := hotp([]byte("12345678901234567890"), uint64(counter)); got != code {
+			t.Errorf("hotp(counter=%d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		unix     int64
+		wantStep int64
+		wantOK   bool
+	}{
+		// RFC 6238 附录 B 的测试向量，取 8 位动态码的后 6 位
+		{"rfc 59", rfcSecret, "287082", 59, 1, true},
+		{"rfc 1111111109", rfcSecret, "081804", 1111111109, 37037036, true},
+		{"rfc 1111111111", rfcSecret, "050471", 1111111111, 37037037, true},
+		{"rfc 1234567890", rfcSecret, "005924", 1234567890, 41152263, true},
+		{"rfc 2000000000", rfcSecret, "279037", 2000000000, 66666666, true},
+		{"previous step within skew", rfcSecret, "287082", 89, 1, true},
+		{"next step within skew", rfcSecret, "287082", 29, 1, true},
+		{"two steps late", rfcSecret, "287082", 119, 0, false},
+		{"two steps early", rfcSecret, "287082", -30, 0, false},
+		{"wrong code", rfcSecret, "287083", 59, 0, false},
+		{"too short", rfcSecret, "28708", 59, 0, false},
+		{"too long", rfcSecret, "2870820", 59, 0, false},
+		{"lowercase secret with spaces", " " + strings.ToLower(rfcSecret) + " ", "287082", 59, 1, true},
+		{"invalid secret", "not-base32!", "287082", 59, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(tt.secret, tt.code, time.Unix(tt.unix, 0))
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP() = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("GenerateTOTPSecret() = %q, want a 160-bit base32 secret", secret)
+	}
+	code := hotp(key, uint64(time.Now().Unix()/totpPeriod))
+	if _, ok := ValidateTOTP(secret, code, time.Now()); !ok {
+		t.Errorf("ValidateTOTP() rejected the current code of a generated secret")
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"net/url"
+	"server/global"
+	"strings"
+)
+
+// APIURL 根据网站地址和路由前缀拼接出后端接口的完整链接，用于邮件中的链接
+func APIURL(path string, query url.Values) string {
+	link := strings.TrimRight(global.Config.Website.URL, "/") + "/" + global.Config.System.RouterPrefix + "/" + strings.TrimLeft(path, "/")
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+// SiteURL 根据网站地址拼接出前端页面的完整链接，用于邮件中的链接
+func SiteURL(path string, query url.Values) string {
+	link := strings.TrimRight(global.Config.Website.URL, "/") + "/" + strings.TrimLeft(path, "/")
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
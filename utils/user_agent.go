@@ -0,0 +1,14 @@
+package utils
+
+import "github.com/ua-parser/uap-go/uaparser"
+
+// ParseUserAgent 解析用户代理（User-Agent）字符串，提取操作系统、设备信息和浏览器信息
+func ParseUserAgent(userAgent string) (os, deviceInfo, browserInfo string) {
+	parser := uaparser.NewFromSaved()
+	cli := parser.Parse(userAgent)
+	os = cli.Os.Family
+	deviceInfo = cli.Device.Family
+	browserInfo = cli.UserAgent.Family
+
+	return
+}